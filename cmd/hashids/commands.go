@@ -0,0 +1,300 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	hashids "github.com/denismitr/go-hashids/v1"
+)
+
+// command turns a single value into a result
+type command interface {
+	register(fs *flag.FlagSet)
+	run(h *hashids.Hasher, value string) result
+	// streamer of plain output, nil when every value needs its own result
+	streamer(h *hashids.Hasher) streamer
+}
+
+// streamer writes plain results of all the lines with hashids streams,
+// failed lines are reported to onError and left empty in the output
+type streamer func(in io.Reader, out io.Writer, onError func(*hashids.LineError)) (failed int, err error)
+
+// result of a single value, Text is written in plain output mode
+type result interface {
+	Text() string
+	Failure() error
+}
+
+type encodeResult struct {
+	Input string `json:"input"`
+	Hash  string `json:"hash,omitempty"`
+	Error string `json:"error,omitempty"`
+	err   error
+}
+
+func (r encodeResult) Text() string   { return r.Hash }
+func (r encodeResult) Failure() error { return r.err }
+
+type encodeCommand struct {
+	hex  bool
+	time bool
+}
+
+func (c *encodeCommand) register(fs *flag.FlagSet) {
+	fs.BoolVar(&c.hex, "hex", false, "values are hexidecimal strings")
+	fs.BoolVar(&c.time, "time", false, "values are RFC3339 timestamps or now")
+}
+
+func (c *encodeCommand) run(h *hashids.Hasher, value string) result {
+	hash, err := c.encode(h, value)
+	if err != nil {
+		return encodeResult{Input: value, Error: err.Error(), err: err}
+	}
+
+	return encodeResult{Input: value, Hash: hash}
+}
+
+func (c *encodeCommand) streamer(h *hashids.Hasher) streamer {
+	if c.hex || c.time {
+		return nil
+	}
+
+	return func(in io.Reader, out io.Writer, onError func(*hashids.LineError)) (int, error) {
+		e := hashids.NewEncoder(out, h).OnError(onError)
+		_, err := e.ReadFrom(in)
+
+		return e.Failed(), err
+	}
+}
+
+func (c *encodeCommand) encode(h *hashids.Hasher, value string) (string, error) {
+	switch {
+	case c.hex:
+		return h.EncodeHex(value)
+	case c.time:
+		t, err := parseTime(value)
+		if err != nil {
+			return "", err
+		}
+
+		return h.EncodeTime(t)
+	}
+
+	numbers, err := hashids.ParseNumbers(value)
+	if err != nil {
+		return "", err
+	}
+
+	return h.Encode(numbers)
+}
+
+type decodeResult struct {
+	Hash    string  `json:"hash"`
+	Numbers []int64 `json:"numbers,omitempty"`
+	Hex     string  `json:"hex,omitempty"`
+	Time    string  `json:"time,omitempty"`
+	Error   string  `json:"error,omitempty"`
+	text    string
+	err     error
+}
+
+func (r decodeResult) Text() string   { return r.text }
+func (r decodeResult) Failure() error { return r.err }
+
+type decodeCommand struct {
+	hex  bool
+	time bool
+}
+
+func (c *decodeCommand) register(fs *flag.FlagSet) {
+	fs.BoolVar(&c.hex, "hex", false, "print hashes as hexidecimal strings")
+	fs.BoolVar(&c.time, "time", false, "print hashes as RFC3339 timestamps")
+}
+
+func (c *decodeCommand) run(h *hashids.Hasher, value string) result {
+	r := decodeResult{Hash: value}
+	decoded := h.Decode(value)
+
+	var err error
+	switch {
+	case c.hex:
+		r.Hex, err = decoded.AsHex()
+		r.text = r.Hex
+	case c.time:
+		var t time.Time
+		if t, err = decoded.AsTime(); err == nil {
+			r.Time = t.Format(time.RFC3339Nano)
+			r.text = r.Time
+		}
+	default:
+		r.Numbers, err = decoded.Unwrap()
+		r.text = joinNumbers(r.Numbers)
+	}
+
+	if err != nil {
+		return decodeResult{Hash: value, Error: err.Error(), err: err}
+	}
+
+	return r
+}
+
+func (c *decodeCommand) streamer(h *hashids.Hasher) streamer {
+	if c.hex || c.time {
+		return nil
+	}
+
+	return func(in io.Reader, out io.Writer, onError func(*hashids.LineError)) (int, error) {
+		d := hashids.NewDecoder(in, h).OnError(onError)
+		_, err := d.WriteTo(out)
+
+		return d.Failed(), err
+	}
+}
+
+type inspectResult struct {
+	Hash    string  `json:"hash"`
+	Length  int     `json:"length"`
+	Valid   bool    `json:"valid"`
+	Numbers []int64 `json:"numbers,omitempty"`
+	Hex     string  `json:"hex,omitempty"`
+	Time    string  `json:"time,omitempty"`
+	Error   string  `json:"error,omitempty"`
+	err     error
+}
+
+func (r inspectResult) Failure() error { return r.err }
+
+func (r inspectResult) Text() string {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "hash:    %s\n", r.Hash)
+	fmt.Fprintf(&sb, "length:  %d\n", r.Length)
+	fmt.Fprintf(&sb, "valid:   %t\n", r.Valid)
+
+	if r.Valid {
+		fmt.Fprintf(&sb, "numbers: %s\n", joinNumbers(r.Numbers))
+	} else {
+		fmt.Fprintf(&sb, "error:   %s\n", r.Error)
+	}
+
+	if r.Hex != "" {
+		fmt.Fprintf(&sb, "hex:     %s\n", r.Hex)
+	}
+
+	if r.Time != "" {
+		fmt.Fprintf(&sb, "time:    %s\n", r.Time)
+	}
+
+	return sb.String()
+}
+
+type inspectCommand struct {
+	time bool
+}
+
+func (c *inspectCommand) register(fs *flag.FlagSet) {
+	fs.BoolVar(&c.time, "time", false, "show hashes of a single number as RFC3339 timestamps")
+}
+
+func (c *inspectCommand) streamer(h *hashids.Hasher) streamer { return nil }
+
+func (c *inspectCommand) run(h *hashids.Hasher, value string) result {
+	r := inspectResult{Hash: value, Length: len([]rune(value))}
+	decoded := h.Decode(value)
+
+	numbers, err := decoded.Unwrap()
+	if err != nil {
+		r.Error = err.Error()
+		r.err = err
+		return r
+	}
+
+	r.Valid = true
+	r.Numbers = numbers
+
+	if hex, err := decoded.AsHex(); err == nil {
+		r.Hex = hex
+	}
+
+	if c.time && len(numbers) == 1 {
+		if t, err := decoded.AsTime(); err == nil {
+			r.Time = t.Format(time.RFC3339Nano)
+		}
+	}
+
+	return r
+}
+
+// writer of the results either as plain text or as JSON lines
+type writer struct {
+	out    io.Writer
+	errs   io.Writer
+	json   bool
+	failed int
+}
+
+func newWriter(out, errs io.Writer, asJSON bool) *writer {
+	return &writer{out: out, errs: errs, json: asJSON}
+}
+
+// write the result of the current line
+func (w *writer) write(lines *hashids.LineReader, r result) {
+	if err := r.Failure(); err != nil {
+		w.failed++
+		if !w.json {
+			w.report(lines.Error(err))
+		}
+	}
+
+	if w.json {
+		data, _ := json.Marshal(r)
+		fmt.Fprintf(w.out, "%s\n", data)
+		return
+	}
+
+	text := r.Text()
+	if !strings.HasSuffix(text, "\n") {
+		text += "\n"
+	}
+
+	fmt.Fprint(w.out, text)
+}
+
+// skip a blank line, plain text keeps it to stay aligned with the input
+func (w *writer) skip() {
+	if !w.json {
+		fmt.Fprintln(w.out)
+	}
+}
+
+// report a failed line
+func (w *writer) report(err *hashids.LineError) {
+	fmt.Fprintln(w.errs, err)
+}
+
+func parseTime(value string) (time.Time, error) {
+	if value == "now" {
+		return time.Now(), nil
+	}
+
+	t, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time %q, expected RFC3339 or now", value)
+	}
+
+	return t, nil
+}
+
+func joinNumbers(numbers []int64) string {
+	parts := make([]string, len(numbers))
+	for i, n := range numbers {
+		parts[i] = strconv.FormatInt(n, 10)
+	}
+
+	return strings.Join(parts, ",")
+}
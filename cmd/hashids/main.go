@@ -0,0 +1,239 @@
+// Command hashids encodes, decodes and inspects hashids from the command line.
+//
+// Usage:
+//
+//	hashids encode [flags] [numbers...]
+//	hashids decode [flags] [hashes...]
+//	hashids inspect [flags] [hashes...]
+//	hashids migrate [flags] [hashes...]
+//
+// Salt, alphabet, length and prefix are taken from flags, then from
+// HASHIDS_SALT, HASHIDS_ALPHABET, HASHIDS_LENGTH and HASHIDS_PREFIX
+// environment variables, then from a JSON config file given with -config
+// or HASHIDS_CONFIG. Without arguments values are read from stdin line by line.
+//
+// The migrate command takes the same settings twice, prefixed with from- and
+// to- for flags and HASHIDS_FROM_ and HASHIDS_TO_ for environment variables.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	hashids "github.com/denismitr/go-hashids/v1"
+)
+
+const usage = `Usage: hashids <command> [flags] [values...]
+
+Commands:
+  encode   encode numbers, numbers of a single hash are comma separated
+  decode   decode hashes
+  inspect  show everything known about hashes
+  migrate  re-encode hashes from one configuration to another
+
+Run hashids <command> -h for the list of flags.
+`
+
+const (
+	exitOK     = 0
+	exitFailed = 1
+	exitUsage  = 2
+)
+
+// config of the hasher, flags override env vars, which override the config file
+type config struct {
+	Salt     string `json:"salt"`
+	Alphabet string `json:"alphabet"`
+	Length   int    `json:"length"`
+	Prefix   string `json:"prefix"`
+}
+
+// env to look up variables, os.Getenv in main
+type env func(string) string
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Getenv, os.Stdin, os.Stdout, os.Stderr))
+}
+
+func run(args []string, getenv env, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return exitUsage
+	}
+
+	var cmd command
+	switch args[0] {
+	case "encode":
+		cmd = &encodeCommand{}
+	case "decode":
+		cmd = &decodeCommand{}
+	case "inspect":
+		cmd = &inspectCommand{}
+	case "migrate":
+		return runMigrate(args[1:], getenv, stdin, stdout, stderr)
+	case "-h", "-help", "--help", "help":
+		fmt.Fprint(stdout, usage)
+		return exitOK
+	default:
+		fmt.Fprintf(stderr, "unknown command %s\n\n%s", args[0], usage)
+		return exitUsage
+	}
+
+	fs := flag.NewFlagSet("hashids "+args[0], flag.ContinueOnError)
+	fs.SetOutput(stderr)
+
+	var o options
+	var asJSON bool
+	o.register(fs)
+	fs.BoolVar(&asJSON, "json", false, "output JSON, one object per line")
+	cmd.register(fs)
+
+	if err := fs.Parse(args[1:]); err != nil {
+		if err == flag.ErrHelp {
+			return exitOK
+		}
+
+		return exitUsage
+	}
+
+	h, err := o.hasher(fs, getenv)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitUsage
+	}
+
+	var in io.Reader = stdin
+	if fs.NArg() > 0 {
+		in = strings.NewReader(strings.Join(fs.Args(), "\n"))
+	}
+
+	w := newWriter(stdout, stderr, asJSON)
+
+	if s := cmd.streamer(h); s != nil && !asJSON {
+		w.failed, err = s(in, stdout, w.report)
+	} else {
+		lines := hashids.NewLineReader(in)
+		for lines.Scan() {
+			if lines.Blank() {
+				w.skip()
+				continue
+			}
+
+			w.write(lines, cmd.run(h, lines.Text()))
+		}
+
+		err = lines.Err()
+	}
+
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitFailed
+	}
+
+	if w.failed > 0 {
+		return exitFailed
+	}
+
+	return exitOK
+}
+
+// options of a hasher, the prefix distinguishes several hashers of one command
+type options struct {
+	prefix string
+	cfg    config
+	config string
+}
+
+func (o *options) register(fs *flag.FlagSet) {
+	fs.StringVar(&o.cfg.Salt, o.prefix+"salt", "", "salt, "+o.env("salt"))
+	fs.StringVar(&o.cfg.Alphabet, o.prefix+"alphabet", "", "alphabet or the name of a preset, "+o.env("alphabet"))
+	fs.IntVar(&o.cfg.Length, o.prefix+"length", 0, "minimal length of the hash, "+o.env("length"))
+	fs.StringVar(&o.cfg.Prefix, o.prefix+"prefix", "", "prefix of the hash, "+o.env("prefix"))
+	fs.StringVar(&o.config, o.prefix+"config", "", "path to a JSON config file, "+o.env("config"))
+}
+
+// env variable name of a setting, e.g. HASHIDS_FROM_SALT for from-salt
+func (o *options) env(name string) string {
+	return "HASHIDS_" + strings.ToUpper(strings.ReplaceAll(o.prefix+name, "-", "_"))
+}
+
+// hashOptions from the config file, env vars and the flags that were set explicitly
+func (o *options) hashOptions(fs *flag.FlagSet, getenv env) (hashids.Options, error) {
+	var cfg config
+
+	path := o.config
+	if path == "" {
+		path = getenv(o.env("config"))
+	}
+
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return hashids.Options{}, fmt.Errorf("unable to read config: %v", err)
+		}
+
+		if err := json.Unmarshal(data, &cfg); err != nil {
+			return hashids.Options{}, fmt.Errorf("invalid config %s: %v", path, err)
+		}
+	}
+
+	if v := getenv(o.env("salt")); v != "" {
+		cfg.Salt = v
+	}
+
+	if v := getenv(o.env("alphabet")); v != "" {
+		cfg.Alphabet = v
+	}
+
+	if v := getenv(o.env("prefix")); v != "" {
+		cfg.Prefix = v
+	}
+
+	if v := getenv(o.env("length")); v != "" {
+		length, err := strconv.Atoi(v)
+		if err != nil {
+			return hashids.Options{}, fmt.Errorf("invalid %s: %v", o.env("length"), err)
+		}
+
+		cfg.Length = length
+	}
+
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case o.prefix + "salt":
+			cfg.Salt = o.cfg.Salt
+		case o.prefix + "alphabet":
+			cfg.Alphabet = o.cfg.Alphabet
+		case o.prefix + "length":
+			cfg.Length = o.cfg.Length
+		case o.prefix + "prefix":
+			cfg.Prefix = o.cfg.Prefix
+		}
+	})
+
+	if preset, err := hashids.Preset(cfg.Alphabet); err == nil {
+		cfg.Alphabet = preset.Alphabet
+	}
+
+	return hashids.Options{
+		Salt:     cfg.Salt,
+		Alphabet: cfg.Alphabet,
+		Length:   cfg.Length,
+		Prefix:   cfg.Prefix,
+	}, nil
+}
+
+// hasher from the config file, env vars and the flags that were set explicitly
+func (o *options) hasher(fs *flag.FlagSet, getenv env) (*hashids.Hasher, error) {
+	opts, err := o.hashOptions(fs, getenv)
+	if err != nil {
+		return nil, err
+	}
+
+	return hashids.New(opts)
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func execute(args []string, vars map[string]string, stdin string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+
+	getenv := func(key string) string { return vars[key] }
+	code := run(args, getenv, strings.NewReader(stdin), &stdout, &stderr)
+
+	return code, stdout.String(), stderr.String()
+}
+
+func Test_EncodeAndDecode(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		name   string
+		args   []string
+		vars   map[string]string
+		stdin  string
+		code   int
+		stdout string
+		stderr string
+	}{
+		{"encode args", []string{"encode", "-salt", "this is my salt", "-length", "8", "45,434,1313,99"}, nil, "", 0, "7nnhzEsDkiYa\n", ""},
+		{"encode env", []string{"encode", "45,434,1313,99"}, map[string]string{"HASHIDS_SALT": "this is my salt", "HASHIDS_LENGTH": "8"}, "", 0, "7nnhzEsDkiYa\n", ""},
+		{"flags override env", []string{"encode", "-salt", "this is my salt", "1"}, map[string]string{"HASHIDS_SALT": "other salt", "HASHIDS_LENGTH": "6"}, "", 0, "B0NV05\n", ""},
+		{"encode prefix", []string{"encode", "-salt", "some salt", "-length", "12", "-alphabet", "lowercase", "-prefix", "cus_", "156"}, nil, "", 0, "cus_2vk4e9xpeng7\n", ""},
+		{"encode stdin", []string{"encode", "-salt", "this is my salt", "-length", "6"}, nil, "1\nabc\n2\n", 1, "B0NV05\n\nLA6m0o\n", "line 2: invalid number \"abc\"\n"},
+		{"encode json", []string{"encode", "-salt", "this is my salt", "-length", "6", "-json", "1", "-1"}, nil, "", 1, "{\"input\":\"1\",\"hash\":\"B0NV05\"}\n{\"input\":\"-1\",\"error\":\"negative numbers like -1 are not allowed\"}\n", ""},
+		{"encode hex", []string{"encode", "-salt", "test salt", "-length", "8", "-hex", "abcdef123456"}, nil, "", 0, "GLC6SKuxIZfQh6hatOc4FKsj\n", ""},
+		{"decode args", []string{"decode", "-salt", "this is my salt", "-length", "8", "7nnhzEsDkiYa"}, nil, "", 0, "45,434,1313,99\n", ""},
+		{"decode stdin", []string{"decode", "-salt", "this is my salt"}, map[string]string{"HASHIDS_LENGTH": "6"}, "B0NV05\nbad\n", 1, "1\n\n", "line 2: mismatch between encoded and decoded values: B0NV05 -> bad, obtained result [1]\n"},
+		{"blank lines", []string{"encode", "-salt", "this is my salt", "-length", "6"}, nil, "1\n\n2\n\n", 0, "B0NV05\n\nLA6m0o\n\n", ""},
+		{"blank lines json", []string{"decode", "-salt", "this is my salt", "-json"}, map[string]string{"HASHIDS_LENGTH": "6"}, "B0NV05\n \n", 0, "{\"hash\":\"B0NV05\",\"numbers\":[1]}\n", ""},
+		{"decode hex", []string{"decode", "-salt", "test salt", "-hex", "GLC6SKuxIZfQh6hatOc4FKsj"}, nil, "", 0, "abcdef123456\n", ""},
+		{"decode json", []string{"decode", "-salt", "this is my salt", "-json", "B0NV05"}, map[string]string{"HASHIDS_LENGTH": "6"}, "", 0, "{\"hash\":\"B0NV05\",\"numbers\":[1]}\n", ""},
+		{"invalid alphabet", []string{"encode", "-alphabet", "abc", "1"}, nil, "", 2, "", "Alphabet length must be at least 10\n"},
+		{"unknown command", []string{"rehash"}, nil, "", 2, "", "unknown command rehash\n\n" + usage},
+		{"encode invalid args", []string{"encode", "-salt", "this is my salt", "-length", "6", "1", "x"}, nil, "", 1, "B0NV05\n\n", "line 2: invalid number \"x\"\n"},
+	}
+
+	for _, tc := range tt {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			code, stdout, stderr := execute(tc.args, tc.vars, tc.stdin)
+
+			assert.Equal(t, tc.code, code)
+			assert.Equal(t, tc.stdout, stdout)
+			assert.Equal(t, tc.stderr, stderr)
+		})
+	}
+}
+
+func Test_EncodeAndDecodeTime(t *testing.T) {
+	t.Parallel()
+
+	code, hash, _ := execute([]string{"encode", "-salt", "salt", "-time", "2020-01-02T03:04:05.123456789Z"}, nil, "")
+	assert.Equal(t, 0, code)
+
+	code, stdout, _ := execute([]string{"decode", "-salt", "salt", "-json", "-time", strings.TrimSpace(hash)}, nil, "")
+	assert.Equal(t, 0, code)
+	assert.Contains(t, stdout, `"time":"2020-01-02T`)
+}
+
+func Test_Help(t *testing.T) {
+	t.Parallel()
+
+	for _, args := range [][]string{{"-h"}, {"encode", "-h"}, {"migrate", "-help"}} {
+		code, _, _ := execute(args, nil, "")
+		assert.Equal(t, 0, code)
+	}
+}
+
+func Test_ConfigFile(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "hashids.json")
+	if err := os.WriteFile(path, []byte(`{"salt": "this is my salt", "length": 8}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	code, stdout, _ := execute([]string{"encode", "-config", path, "45,434,1313,99"}, nil, "")
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "7nnhzEsDkiYa\n", stdout)
+
+	code, stdout, _ = execute([]string{"encode", "-length", "6", "1"}, map[string]string{"HASHIDS_CONFIG": path}, "")
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "B0NV05\n", stdout)
+}
+
+func Test_Inspect(t *testing.T) {
+	t.Parallel()
+
+	code, stdout, stderr := execute([]string{"inspect", "-salt", "this is my salt", "-length", "8", "7nnhzEsDkiYa", "invalid"}, nil, "")
+	assert.Equal(t, 1, code)
+	assert.Contains(t, stdout, "hash:    7nnhzEsDkiYa\nlength:  12\nvalid:   true\nnumbers: 45,434,1313,99\n")
+	assert.Contains(t, stdout, "hash:    invalid\nlength:  7\nvalid:   false\nerror:   ")
+	assert.Contains(t, stderr, "line 2: ")
+
+	code, stdout, _ = execute([]string{"inspect", "-salt", "this is my salt", "-length", "6", "B0NV05"}, nil, "")
+	assert.Equal(t, 0, code)
+	assert.NotContains(t, stdout, "time:")
+
+	code, stdout, _ = execute([]string{"inspect", "-salt", "this is my salt", "-length", "6", "-time", "B0NV05"}, nil, "")
+	assert.Equal(t, 0, code)
+	assert.Contains(t, stdout, "time:    ")
+}
+
+func Test_Migrate(t *testing.T) {
+	t.Parallel()
+
+	from := []string{"migrate", "-from-salt", "this is my salt", "-from-length", "8", "-to-salt", "new salt", "-to-prefix", "id_"}
+
+	tt := []struct {
+		name   string
+		args   []string
+		vars   map[string]string
+		stdin  string
+		code   int
+		stdout string
+		stderr string
+	}{
+		{"args", append(from, "7nnhzEsDkiYa"), nil, "", 0, "old,new\n7nnhzEsDkiYa,id_RPPULqtmps8W\n", "migrated 1 of 1, failed 0\n"},
+		{"env", []string{"migrate", "-from-length", "8", "7nnhzEsDkiYa"}, map[string]string{"HASHIDS_FROM_SALT": "this is my salt", "HASHIDS_TO_SALT": "new salt", "HASHIDS_TO_PREFIX": "id_"}, "", 0, "old,new\n7nnhzEsDkiYa,id_RPPULqtmps8W\n", "migrated 1 of 1, failed 0\n"},
+		{"stdin", from, nil, "7nnhzEsDkiYa\nbad\n", 1, "old,new\n7nnhzEsDkiYa,id_RPPULqtmps8W\n", "line 2: mismatch between encoded and decoded values: gB0NV05e -> bad, obtained result [1]\nmigrated 1 of 2, failed 1\n"},
+		{"csv", append(from, "-csv", "id"), nil, "name,id\nfirst,7nnhzEsDkiYa\n", 0, "old,new\n7nnhzEsDkiYa,id_RPPULqtmps8W\n", "migrated 1 of 1, failed 0\n"},
+		{"jsonl", append(from, "-jsonl", "id"), nil, "{\"id\":\"7nnhzEsDkiYa\"}\n", 0, "old,new\n7nnhzEsDkiYa,id_RPPULqtmps8W\n", "migrated 1 of 1, failed 0\n"},
+		{"csv and jsonl", append(from, "-csv", "id", "-jsonl", "id"), nil, "", 2, "", "-csv and -jsonl cannot be used together\n"},
+		{"invalid options", []string{"migrate", "-to-alphabet", "abc"}, nil, "", 2, "", "invalid options to migrate to: Alphabet length must be at least 10\n"},
+	}
+
+	for _, tc := range tt {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			code, stdout, stderr := execute(tc.args, tc.vars, tc.stdin)
+
+			assert.Equal(t, tc.code, code)
+			assert.Equal(t, tc.stdout, stdout)
+			assert.Equal(t, tc.stderr, stderr)
+		})
+	}
+}
+
+func Test_MigrateToFile(t *testing.T) {
+	t.Parallel()
+
+	out := filepath.Join(t.TempDir(), "mapping.csv")
+	args := []string{"migrate", "-from-salt", "this is my salt", "-from-length", "8", "-to-salt", "new salt", "-to-prefix", "id_", "-out", out, "7nnhzEsDkiYa"}
+
+	code, stdout, _ := execute(args, nil, "")
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "", stdout)
+
+	mapping, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, "old,new\n7nnhzEsDkiYa,id_RPPULqtmps8W\n", string(mapping))
+
+	args[len(args)-2] = filepath.Join(out, "missing", "mapping.csv")
+	code, _, _ = execute(args, nil, "")
+	assert.Equal(t, 1, code)
+}
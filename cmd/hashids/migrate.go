@@ -0,0 +1,111 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	hashids "github.com/denismitr/go-hashids/v1"
+)
+
+// runMigrate decodes hashes with the from- configuration, encodes them with
+// the to- one and writes old,new CSV mapping, failures are reported to stderr
+func runMigrate(args []string, getenv env, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("hashids migrate", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+
+	from := options{prefix: "from-"}
+	to := options{prefix: "to-"}
+	from.register(fs)
+	to.register(fs)
+
+	var column, field, out string
+	fs.StringVar(&column, "csv", "", "input is CSV, hashes are in the column with this header")
+	fs.StringVar(&field, "jsonl", "", "input is JSON lines, hashes are in this field")
+	fs.StringVar(&out, "out", "", "path to write the mapping to instead of stdout")
+
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return exitOK
+		}
+
+		return exitUsage
+	}
+
+	if column != "" && field != "" {
+		fmt.Fprintln(stderr, "-csv and -jsonl cannot be used together")
+		return exitUsage
+	}
+
+	m, err := newMigrator(fs, getenv, from, to)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitUsage
+	}
+
+	var in io.Reader = stdin
+	if fs.NArg() > 0 {
+		in = strings.NewReader(strings.Join(fs.Args(), "\n"))
+	}
+
+	var w io.Writer = stdout
+	var f *os.File
+	if out != "" {
+		if f, err = os.Create(out); err != nil {
+			fmt.Fprintln(stderr, err)
+			return exitFailed
+		}
+
+		w = f
+	}
+
+	var report *hashids.MigrationReport
+	switch {
+	case column != "":
+		report, err = m.MigrateCSV(in, w, column)
+	case field != "":
+		report, err = m.MigrateJSONL(in, w, field)
+	default:
+		report, err = m.MigrateValues(in, w)
+	}
+
+	// the mapping is not complete until the file is closed
+	if f != nil {
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+	}
+
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitFailed
+	}
+
+	for _, failure := range report.Failed {
+		fmt.Fprintln(stderr, failure)
+	}
+
+	fmt.Fprintf(stderr, "migrated %d of %d, failed %d\n", report.Migrated, report.Total, len(report.Failed))
+
+	if len(report.Failed) > 0 {
+		return exitFailed
+	}
+
+	return exitOK
+}
+
+func newMigrator(fs *flag.FlagSet, getenv env, from, to options) (*hashids.Migrator, error) {
+	fromOptions, err := from.hashOptions(fs, getenv)
+	if err != nil {
+		return nil, err
+	}
+
+	toOptions, err := to.hashOptions(fs, getenv)
+	if err != nil {
+		return nil, err
+	}
+
+	return hashids.NewMigrator(fromOptions, toOptions)
+}
@@ -209,3 +209,73 @@ func Test_CustomAlphabetWithExplicitPrefix(t *testing.T) {
 		})
 	}
 }
+
+func Test_SmallAlphabets(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		alphabet string
+		input    []int64
+		length   int
+	}{
+		{NumericAlphabet, []int64{1}, 0},
+		{NumericAlphabet, []int64{1}, 8},
+		{NumericAlphabet, []int64{1, 2, 3}, 0},
+		{NumericAlphabet, []int64{1, 2, 3}, 12},
+		{NumericAlphabet, []int64{100500, 0, 99}, 6},
+		{NumericAlphabet, []int64{9223372036854775807, 9223372036854775807}, 0},
+		{"0123456789ABCDEF", []int64{45, 434, 1313, 99}, 10},
+		{"cfhistuCFH", []int64{1, 2, 3}, 0},
+		{"cfhistuCFH", []int64{1234, 56}, 16},
+		{"cfhistuCF1", []int64{7, 8, 9}, 4},
+	}
+
+	for _, tc := range tt {
+		tc := tc
+
+		t.Run(fmt.Sprintf("Alphabet %s input %v", tc.alphabet, tc.input), func(t *testing.T) {
+			options := Options{
+				Length:   tc.length,
+				Salt:     "test salt",
+				Alphabet: tc.alphabet,
+			}
+
+			h, err := New(options)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			hash, err := h.Encode(tc.input)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(hash) < tc.length {
+				t.Fatalf("Expected hash %s to be at least %d characters long", hash, tc.length)
+			}
+
+			for _, r := range hash {
+				assert.Contains(t, tc.alphabet, string(r))
+			}
+
+			result, err := h.Decode(hash).Unwrap()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			assert.Equal(t, tc.input, result)
+		})
+	}
+}
+
+func Test_AlphabetTooShort(t *testing.T) {
+	options := DefaultOptions("test salt")
+	options.Alphabet = "123456789"
+
+	_, err := New(options)
+	if err == nil {
+		t.Fatal("err should not be nil")
+	}
+
+	assert.Contains(t, err.Error(), "Alphabet length must be at least 10")
+}
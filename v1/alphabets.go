@@ -0,0 +1,135 @@
+package hashids
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// Base58Alphabet - Bitcoin style Base58, no 0, O, I and l
+	Base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+	// CrockfordBase32Alphabet - Crockford's Base32, upper case, no I, L, O and U
+	CrockfordBase32Alphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+	// URLSafeAlphabet - letters and digits that cannot be confused with one another
+	// when read aloud or typed by hand, no 0, O, o, 1, I, l
+	URLSafeAlphabet = "abcdefghijkmnpqrstuvwxyzABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+	// HexAlphabet - lower case hexidecimal digits, hashes look like hex strings
+	HexAlphabet = "0123456789abcdef"
+
+	// LowercaseLetters character class
+	LowercaseLetters = "abcdefghijklmnopqrstuvwxyz"
+	// UppercaseLetters character class
+	UppercaseLetters = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	// Digits character class
+	Digits = "0123456789"
+	// Confusables - characters that are easily mistaken for one another
+	Confusables = "0Oo1Il"
+)
+
+// AlphabetPreset - named alphabet with a recommended hash length
+type AlphabetPreset struct {
+	Name        string
+	Alphabet    string
+	Length      int
+	Description string
+}
+
+// Options with the preset alphabet and recommended length
+func (p AlphabetPreset) Options(salt string) Options {
+	return Options{
+		Alphabet: p.Alphabet,
+		Length:   p.Length,
+		Salt:     salt,
+	}
+}
+
+// presets are kept in a slice, so the order is stable
+var presets = []AlphabetPreset{
+	{"default", DefaultAlphabet, 8, "all latin letters and digits, 62 characters"},
+	{"lowercase", LowercaseAlphabetWithDigits, 10, "lower case latin letters and digits, 36 characters, case insensitive"},
+	{"numeric", NumericAlphabet, 12, "digits only, 10 characters, for order numbers and PIN-like references"},
+	{"base58", Base58Alphabet, 8, "Bitcoin Base58, 58 characters, no 0, O, I and l"},
+	{"crockford32", CrockfordBase32Alphabet, 10, "Crockford Base32, 32 characters, upper case, no I, L, O and U"},
+	{"urlsafe", URLSafeAlphabet, 8, "letters and digits without confusables, 56 characters"},
+	{"hex", HexAlphabet, 12, "hexidecimal digits, 16 characters"},
+}
+
+// Presets - all built-in alphabet presets
+func Presets() []AlphabetPreset {
+	out := make([]AlphabetPreset, len(presets))
+	copy(out, presets)
+	return out
+}
+
+// Preset by name
+func Preset(name string) (AlphabetPreset, error) {
+	for _, p := range presets {
+		if p.Name == name {
+			return p, nil
+		}
+	}
+
+	return AlphabetPreset{}, fmt.Errorf("unknown alphabet preset %s", name)
+}
+
+// AlphabetBuilder composes an alphabet from character classes
+type AlphabetBuilder struct {
+	classes []string
+	exclude string
+}
+
+// NewAlphabetBuilder with no characters
+func NewAlphabetBuilder() *AlphabetBuilder {
+	return &AlphabetBuilder{}
+}
+
+// With adds character classes, duplicates are skipped
+func (b *AlphabetBuilder) With(classes ...string) *AlphabetBuilder {
+	b.classes = append(b.classes, classes...)
+
+	return b
+}
+
+// Without excludes characters
+func (b *AlphabetBuilder) Without(chars string) *AlphabetBuilder {
+	b.exclude += chars
+
+	return b
+}
+
+// WithoutConfusables excludes characters that are easily mistaken for one another
+func (b *AlphabetBuilder) WithoutConfusables() *AlphabetBuilder {
+	return b.Without(Confusables)
+}
+
+// Build the alphabet and validate it, an empty alphabet is an error too
+func (b *AlphabetBuilder) Build() (string, error) {
+	var sb strings.Builder
+	seen := make(map[rune]bool)
+
+	for _, class := range b.classes {
+		for _, r := range class {
+			if seen[r] || strings.ContainsRune(b.exclude, r) {
+				continue
+			}
+
+			seen[r] = true
+			sb.WriteRune(r)
+		}
+	}
+
+	// an empty alphabet would silently fall back to the default one
+	alphabet := sb.String()
+	if alphabet == "" {
+		return "", fmt.Errorf("Alphabet length must be at least %d", MinAlphabetLength)
+	}
+
+	if _, err := (Options{Alphabet: alphabet}).validateAlphabet(); err != nil {
+		return "", err
+	}
+
+	return alphabet, nil
+}
@@ -0,0 +1,119 @@
+package hashids
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_PresetsRoundTripWithRecommendedLength(t *testing.T) {
+	t.Parallel()
+
+	for _, p := range Presets() {
+		p := p
+
+		t.Run(p.Name, func(t *testing.T) {
+			h, err := New(p.Options("test salt"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for _, n := range []int64{0, 1, 2, 99, 1000, 65535} {
+				hash, err := h.Encode(n)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				assert.Equal(t, p.Length, len(hash), hash)
+
+				for _, r := range hash {
+					assert.Contains(t, p.Alphabet, string(r))
+				}
+
+				result, err := h.Decode(hash).FirstInt64()
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				assert.Equal(t, n, result)
+			}
+		})
+	}
+}
+
+func Test_PresetByName(t *testing.T) {
+	p, err := Preset("base58")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, Base58Alphabet, p.Alphabet)
+
+	_, err = Preset("base64")
+	assert.Contains(t, err.Error(), "unknown alphabet preset base64")
+}
+
+func Test_AlphabetBuilder(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		name     string
+		builder  *AlphabetBuilder
+		alphabet string
+		err      string
+	}{
+		{
+			name:     "digits and lowercase",
+			builder:  NewAlphabetBuilder().With(Digits, LowercaseLetters),
+			alphabet: Digits + LowercaseLetters,
+		},
+		{
+			name:     "duplicates are skipped",
+			builder:  NewAlphabetBuilder().With(Digits, HexAlphabet),
+			alphabet: HexAlphabet,
+		},
+		{
+			name:     "without confusables",
+			builder:  NewAlphabetBuilder().With(Digits, UppercaseLetters).WithoutConfusables(),
+			alphabet: "23456789ABCDEFGHJKLMNPQRSTUVWXYZ",
+		},
+		{
+			name:    "too short",
+			builder: NewAlphabetBuilder().With(Digits).Without("9"),
+			err:     "Alphabet length must be at least 10",
+		},
+		{
+			name:    "empty",
+			builder: NewAlphabetBuilder(),
+			err:     "Alphabet length must be at least 10",
+		},
+		{
+			name:    "everything excluded",
+			builder: NewAlphabetBuilder().With(Digits).Without(Digits),
+			err:     "Alphabet length must be at least 10",
+		},
+		{
+			name:    "spaces are not allowed",
+			builder: NewAlphabetBuilder().With(Digits, " "),
+			err:     "alphabet may not contain empty spaces",
+		},
+	}
+
+	for _, tc := range tt {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			alphabet, err := tc.builder.Build()
+			if tc.err != "" {
+				assert.Contains(t, err.Error(), tc.err)
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			assert.Equal(t, tc.alphabet, alphabet)
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package hashids
+
+import (
+	"runtime"
+	"sync"
+)
+
+// minParallelBatch - smaller batches are not worth spreading over goroutines
+const minParallelBatch = 256
+
+// EncodeBatch of groups of numbers, errors are reported per item
+func (h *Hasher) EncodeBatch(batch [][]int64) ([]string, []error) {
+	hashes := make([]string, len(batch))
+	errs := make([]error, len(batch))
+
+	h.encodeBatch(batch, hashes, errs)
+
+	return hashes, errs
+}
+
+// DecodeBatch of hashes, every hash gets its own result
+func (h *Hasher) DecodeBatch(hashes []string) []*DecodedResult {
+	results := make([]*DecodedResult, len(hashes))
+
+	h.decodeBatch(hashes, results)
+
+	return results
+}
+
+// EncodeBatchParallel spreads the batch over GOMAXPROCS goroutines,
+// order of the results matches the order of the batch
+func (h *Hasher) EncodeBatchParallel(batch [][]int64) ([]string, []error) {
+	hashes := make([]string, len(batch))
+	errs := make([]error, len(batch))
+
+	h.parallel(len(batch), func(w *Hasher, from, to int) {
+		w.encodeBatch(batch[from:to], hashes[from:to], errs[from:to])
+	})
+
+	return hashes, errs
+}
+
+// DecodeBatchParallel spreads the hashes over GOMAXPROCS goroutines,
+// order of the results matches the order of the hashes
+func (h *Hasher) DecodeBatchParallel(hashes []string) []*DecodedResult {
+	results := make([]*DecodedResult, len(hashes))
+
+	h.parallel(len(hashes), func(w *Hasher, from, to int) {
+		w.decodeBatch(hashes[from:to], results[from:to])
+	})
+
+	return results
+}
+
+func (h *Hasher) encodeBatch(batch [][]int64, hashes []string, errs []error) {
+	for i, numbers := range batch {
+		h.reset()
+
+		err := h.hashNumbers(numbers)
+		if err == nil {
+			err = h.checkLength()
+		}
+
+		if err != nil {
+			errs[i] = err
+			continue
+		}
+
+		hashes[i] = h.encoded(numbers)
+	}
+}
+
+func (h *Hasher) decodeBatch(hashes []string, results []*DecodedResult) {
+	for i, hash := range hashes {
+		results[i] = h.Decode(hash)
+	}
+}
+
+// parallel runs f over contiguous chunks of n items, every goroutine
+// gets its own copy of the hasher, since hasher buffers are not shared safely
+func (h *Hasher) parallel(n int, f func(w *Hasher, from, to int)) {
+	workers := runtime.GOMAXPROCS(0)
+	if n < minParallelBatch || workers == 1 {
+		f(h, 0, n)
+		return
+	}
+
+	if workers > n/minParallelBatch {
+		workers = n / minParallelBatch
+	}
+
+	chunk := (n + workers - 1) / workers
+
+	var wg sync.WaitGroup
+	for from := 0; from < n; from += chunk {
+		to := from + chunk
+		if to > n {
+			to = n
+		}
+
+		wg.Add(1)
+		go func(from, to int) {
+			defer wg.Done()
+			f(h.Clone(), from, to)
+		}(from, to)
+	}
+
+	wg.Wait()
+}
+
+// Clone for use in another goroutine, the copy shares
+// the read only tables, but not the buffers
+func (h *Hasher) Clone() *Hasher {
+	w := &Hasher{
+		options:            h.options,
+		maxLengthPerNumber: h.maxLengthPerNumber,
+		now:                h.now,
+	}
+
+	if h.tagger != nil {
+		w.tagger = h.tagger.clone()
+	}
+
+	if h.bytes != nil {
+		w.bytes = h.bytes.clone()
+	} else {
+		w.runes = h.runes.clone()
+	}
+
+	return w
+}
@@ -0,0 +1,109 @@
+package hashids
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func batchOf(n int) [][]int64 {
+	batch := make([][]int64, n)
+	for i := range batch {
+		batch[i] = []int64{int64(i), int64(i * 7)}
+	}
+
+	return batch
+}
+
+func Test_EncodeBatchAndDecodeBatch(t *testing.T) {
+	t.Parallel()
+
+	h, _ := New(Options{Salt: "test salt", Length: 8, MaxLength: 10})
+
+	batch := [][]int64{{1}, {1, 2, 3}, {}, {5, -5}, {1000}, {123456789, 987654321}}
+
+	hashes, errs := h.EncodeBatch(batch)
+	assert.Equal(t, len(batch), len(hashes))
+	assert.Equal(t, len(batch), len(errs))
+
+	for i, numbers := range batch {
+		expected, err := h.Encode(numbers)
+		assert.Equal(t, expected, hashes[i], fmt.Sprint(numbers))
+		assert.Equal(t, fmt.Sprint(err), fmt.Sprint(errs[i]), fmt.Sprint(numbers))
+	}
+
+	results := h.DecodeBatch(append(hashes, "invalid"))
+	assert.Equal(t, len(batch)+1, len(results))
+
+	for i, numbers := range batch {
+		if errs[i] != nil {
+			continue
+		}
+
+		decoded, err := results[i].Unwrap()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, numbers, decoded)
+	}
+
+	assert.True(t, results[len(batch)].HasError())
+}
+
+func Test_ParallelBatchKeepsOrder(t *testing.T) {
+	t.Parallel()
+
+	for _, o := range []Options{DefaultOptions("test salt"), {Salt: "соль", Alphabet: "абвгдежзиклмнпрсто1234"}} {
+		h, err := New(o)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		batch := batchOf(5000)
+		batch[4321] = []int64{-1}
+
+		expected, expectedErrs := h.EncodeBatch(batch)
+		hashes, errs := h.EncodeBatchParallel(batch)
+
+		assert.Equal(t, expected, hashes)
+		assert.Equal(t, expectedErrs, errs)
+		assert.Equal(t, "negative numbers like -1 are not allowed", errs[4321].Error())
+
+		results := h.DecodeBatchParallel(hashes)
+		for i, numbers := range batch {
+			if i == 4321 {
+				assert.True(t, results[i].HasError())
+				continue
+			}
+
+			decoded, err := results[i].Unwrap()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			assert.Equal(t, numbers, decoded)
+		}
+	}
+}
+
+func Benchmark_EncodeBatch(b *testing.B) {
+	h, _ := New(DefaultOptions("test salt"))
+	batch := batchOf(10000)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		h.EncodeBatch(batch)
+	}
+}
+
+func Benchmark_EncodeBatchParallel(b *testing.B) {
+	h, _ := New(DefaultOptions("test salt"))
+	batch := batchOf(10000)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		h.EncodeBatchParallel(batch)
+	}
+}
@@ -0,0 +1,156 @@
+package hashids
+
+import (
+	"fmt"
+	"math"
+	"unicode/utf8"
+)
+
+// maxPermutedStats - numbers Stats counts one by one when they are permuted
+const maxPermutedStats = 1 << 16
+
+// LengthStats - distribution of hash lengths over a range of numbers
+type LengthStats struct {
+	Min    int
+	Max    int
+	Counts map[int]int64
+}
+
+// EncodedLen of the hash in characters for given numbers including prefix,
+// no hash is allocated
+func (h *Hasher) EncodedLen(numbers ...int64) (int, error) {
+	if len(numbers) == 0 {
+		return 0, fmt.Errorf("cannot encode an empty slice of numbers")
+	}
+
+	// lottery character and a separator between each pair of numbers
+	length := len(numbers)
+	for _, n := range numbers {
+		if n < 0 {
+			return 0, fmt.Errorf("negative numbers like %d are not allowed", n)
+		}
+
+		if p := h.permutation(); p != nil {
+			var err error
+			if n, err = p.forward(n); err != nil {
+				return 0, err
+			}
+		}
+
+		length += numberHashLen(n, int64(len(h.options.alphabet)))
+	}
+
+	if h.options.OrderPreserving {
+		length = len(numbers) * h.options.orderedWidth()
+	}
+
+	return h.paddedLen(length), nil
+}
+
+// MaxEncodedLen of the hash of count numbers including prefix
+func (h *Hasher) MaxEncodedLen(count int) int {
+	if count <= 0 {
+		return 0
+	}
+
+	length := count * (numberHashLen(math.MaxInt64, int64(len(h.options.alphabet))) + 1)
+	if h.options.OrderPreserving {
+		length = count * h.options.orderedWidth()
+	}
+
+	return h.paddedLen(length)
+}
+
+// Stats of hash lengths for every single number in a range from..to inclusive
+func (h *Hasher) Stats(from, to int64) (*LengthStats, error) {
+	if from < 0 || to < from {
+		return nil, fmt.Errorf("invalid range %d..%d", from, to)
+	}
+
+	stats := &LengthStats{Counts: make(map[int]int64)}
+
+	if h.options.OrderPreserving {
+		length := h.paddedLen(h.options.orderedWidth())
+		stats.Min, stats.Max = length, length
+		// the count of 0..MaxInt64 does not fit into int64
+		stats.Counts[length] = math.MaxInt64
+		if to-from < math.MaxInt64 {
+			stats.Counts[length] = to - from + 1
+		}
+
+		return stats, nil
+	}
+
+	if h.permutation() != nil {
+		return h.permutedStats(from, to, stats)
+	}
+
+	base := int64(len(h.options.alphabet))
+
+	// numbers with the same count of digits in the base of the alphabet
+	// all have hashes of the same length
+	lo, hi := int64(0), base-1
+	for digits := 1; lo <= to; digits++ {
+		if hi >= from {
+			count := min64(hi, to) - max64(lo, from) + 1
+			length := h.paddedLen(digits + 1)
+			stats.Counts[length] += count
+
+			if stats.Min == 0 || length < stats.Min {
+				stats.Min = length
+			}
+
+			if length > stats.Max {
+				stats.Max = length
+			}
+		}
+
+		if hi == math.MaxInt64 {
+			break
+		}
+
+		lo = hi + 1
+		if hi > (math.MaxInt64-base+1)/base {
+			hi = math.MaxInt64
+		} else {
+			hi = hi*base + base - 1
+		}
+	}
+
+	return stats, nil
+}
+
+// permutedStats - permuted numbers have no ranges of the same length,
+// so every number is counted on its own
+func (h *Hasher) permutedStats(from, to int64, stats *LengthStats) (*LengthStats, error) {
+	if to-from >= maxPermutedStats {
+		return nil, fmt.Errorf("range %d..%d is too large, at most %d permuted numbers are counted", from, to, maxPermutedStats)
+	}
+
+	for n := from; n <= to; n++ {
+		length, err := h.EncodedLen(n)
+		if err != nil {
+			return nil, err
+		}
+
+		stats.Counts[length]++
+
+		if stats.Min == 0 || length < stats.Min {
+			stats.Min = length
+		}
+
+		if length > stats.Max {
+			stats.Max = length
+		}
+	}
+
+	return stats, nil
+}
+
+func (h *Hasher) paddedLen(length int) int {
+	if length < h.options.Length {
+		length = h.options.Length
+	}
+
+	return length + utf8.RuneCountInString(h.options.Prefix) + h.options.TagLength
+}
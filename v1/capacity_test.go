@@ -0,0 +1,166 @@
+package hashids
+
+import (
+	"fmt"
+	"math"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var capacityOptions = []Options{
+	DefaultOptions("test salt"),
+	{Salt: "test salt"},
+	{Salt: "test salt", Alphabet: NumericAlphabet, Length: 6, Prefix: "ord_"},
+	{Salt: "test salt", Alphabet: "абвгдежзиклмнпрсто1234", Length: 8, Prefix: "преф_"},
+}
+
+func Test_EncodedLenMatchesEncode(t *testing.T) {
+	t.Parallel()
+
+	inputs := [][]int64{
+		{0},
+		{1},
+		{35},
+		{36},
+		{1, 2, 3},
+		{45, 434, 1313, 99},
+		{100500, 0, 99},
+		{math.MaxInt64},
+		{math.MaxInt64, math.MaxInt64, 1},
+	}
+
+	for i, options := range capacityOptions {
+		h, err := New(options)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, input := range inputs {
+			t.Run(fmt.Sprintf("options %d input %v", i, input), func(t *testing.T) {
+				hash, err := h.Encode(input)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				length, err := h.EncodedLen(input...)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				assert.Equal(t, utf8.RuneCountInString(hash), length)
+				assert.True(t, length <= h.MaxEncodedLen(len(input)))
+			})
+		}
+	}
+}
+
+func Test_EncodedLenErrors(t *testing.T) {
+	h, _ := New(DefaultOptions("test salt"))
+
+	_, err := h.EncodedLen()
+	assert.Equal(t, "cannot encode an empty slice of numbers", err.Error())
+
+	_, err = h.EncodedLen(1, -1)
+	assert.Equal(t, "negative numbers like -1 are not allowed", err.Error())
+}
+
+func Test_MaxEncodedLen(t *testing.T) {
+	t.Parallel()
+
+	for i, options := range capacityOptions {
+		h, err := New(options)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for count := 1; count <= 4; count++ {
+			input := make([]int64, count)
+			for j := range input {
+				input[j] = math.MaxInt64
+			}
+
+			hash, err := h.Encode(input)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			assert.Equal(t, utf8.RuneCountInString(hash), h.MaxEncodedLen(count), fmt.Sprintf("options %d count %d", i, count))
+		}
+
+		assert.Equal(t, 0, h.MaxEncodedLen(0))
+	}
+}
+
+func Test_Stats(t *testing.T) {
+	t.Parallel()
+
+	ranges := []struct {
+		from int64
+		to   int64
+	}{
+		{0, 0},
+		{0, 5000},
+		{30, 2000},
+		{1295, 1297},
+	}
+
+	for i, options := range capacityOptions {
+		h, err := New(options)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, r := range ranges {
+			t.Run(fmt.Sprintf("options %d range %d..%d", i, r.from, r.to), func(t *testing.T) {
+				expected := &LengthStats{Counts: make(map[int]int64)}
+				for n := r.from; n <= r.to; n++ {
+					hash, err := h.Encode(n)
+					if err != nil {
+						t.Fatal(err)
+					}
+
+					length := utf8.RuneCountInString(hash)
+					expected.Counts[length]++
+
+					if expected.Min == 0 || length < expected.Min {
+						expected.Min = length
+					}
+
+					if length > expected.Max {
+						expected.Max = length
+					}
+				}
+
+				stats, err := h.Stats(r.from, r.to)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				assert.Equal(t, expected, stats)
+			})
+		}
+	}
+}
+
+func Test_StatsOfFullRange(t *testing.T) {
+	h, _ := New(Options{Salt: "test salt", Alphabet: NumericAlphabet})
+
+	stats, err := h.Stats(1, math.MaxInt64)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var total int64
+	for _, count := range stats.Counts {
+		total += count
+	}
+
+	assert.Equal(t, int64(math.MaxInt64), total)
+	assert.Equal(t, 2, stats.Min)
+	assert.Equal(t, h.MaxEncodedLen(1), stats.Max)
+
+	_, err = h.Stats(10, 1)
+	assert.Equal(t, "invalid range 10..1", err.Error())
+}
@@ -0,0 +1,411 @@
+package hashids
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// codec hashes numbers over an alphabet of symbols, byte for ASCII only
+// configurations and rune for the rest, reusing its buffers between calls
+type codec[T symbol] struct {
+	alphabet []T
+	salt     []T
+	seps     []T
+	guards   []T
+	length   int
+	// maxLength of the hash, 0 means no limit
+	maxLength int
+
+	index    lookup[T]
+	sepSet   symbolSet[T]
+	guardSet symbolSet[T]
+	tables   []table[T]
+	// steps - tables of the numbers after the first one by lottery, filled as they
+	// are needed and never shared with clones
+	steps     [][]table[T]
+	stepsSize int
+	// ordered replaces the hashing in order preserving mode
+	ordered *ordered[T]
+	// perm of the numbers before hashing, nil if they are hashed as they are
+	perm permutation
+
+	hash     []T
+	buf      []T
+	pad      []T
+	shuffled []T
+	input    []T
+	permuted []int64
+}
+
+func newCodec[T symbol](o Options) *codec[T] {
+	c := &codec[T]{
+		alphabet: toSymbols[T](o.alphabet),
+		salt:     toSymbols[T](o.salt),
+		seps:     toSymbols[T](o.seps),
+		guards:   toSymbols[T](o.guards),
+		length:   o.Length,
+
+		maxLength: o.maxLength(),
+	}
+
+	if o.OrderPreserving {
+		c.ordered = newOrdered[T](o)
+	} else {
+		c.buildTables()
+	}
+
+	return c
+}
+
+// clone shares the read only tables, but not the buffers
+func (c *codec[T]) clone() *codec[T] {
+	w := &codec[T]{
+		alphabet:  c.alphabet,
+		salt:      c.salt,
+		seps:      c.seps,
+		guards:    c.guards,
+		length:    c.length,
+		maxLength: c.maxLength,
+		index:     c.index,
+		sepSet:    c.sepSet,
+		guardSet:  c.guardSet,
+		tables:    c.tables,
+		ordered:   c.ordered,
+	}
+
+	if c.steps != nil {
+		w.steps = make([][]table[T], len(c.steps))
+	}
+
+	if c.perm != nil {
+		w.perm = c.perm.clone()
+	}
+
+	return w
+}
+
+func (c *codec[T]) reset() {
+	c.hash = c.hash[:0]
+}
+
+// hashNumbers into c.hash
+func (c *codec[T]) hashNumbers(numbers []int64) error {
+	if len(numbers) == 0 {
+		return fmt.Errorf("cannot encode an empty slice of numbers")
+	}
+
+	for _, n := range numbers {
+		if n < 0 {
+			return fmt.Errorf("negative numbers like %d are not allowed", n)
+		}
+	}
+
+	if c.ordered != nil {
+		c.hash = c.ordered.appendHash(c.hash, numbers)
+		return nil
+	}
+
+	if c.perm != nil {
+		var err error
+		if c.permuted, err = permuteAll(c.permuted[:0], numbers, c.perm); err != nil {
+			return err
+		}
+
+		numbers = c.permuted
+	}
+
+	numbersHashInt := createNumbersHashInt(numbers)
+	lotteryIndex := numbersHashInt % int64(len(c.alphabet))
+	lottery := c.alphabet[lotteryIndex]
+
+	c.hash = append(c.hash, lottery)
+
+	var alphabet []T
+	for i, n := range numbers {
+		alphabet, _ = c.step(i, alphabet, lottery, int(lotteryIndex))
+
+		start := len(c.hash)
+		c.hash = appendHash(c.hash, n, alphabet)
+
+		if i < len(numbers)-1 {
+			n %= int64(c.hash[start]) + int64(i)
+			c.hash = append(c.hash, c.seps[n%int64(len(c.seps))])
+		}
+	}
+
+	// only padding shuffles the alphabet, which may come straight from the tables
+	if len(c.hash)+2 < c.length {
+		c.shuffled = append(c.shuffled[:0], alphabet...)
+	}
+
+	c.extendHash(c.shuffled, numbersHashInt, c.padded(len(numbers), int(lotteryIndex)))
+
+	return nil
+}
+
+// padded - precomputed first padding shuffle, only known for hashes of a single number
+func (c *codec[T]) padded(count, lotteryIndex int) []T {
+	if count != 1 || c.tables == nil || lotteryIndex < 0 {
+		return nil
+	}
+
+	return c.tables[lotteryIndex].padded
+}
+
+// extendHash with guards and padding up to c.length, padded when given
+// replaces the first shuffle of the alphabet
+func (c *codec[T]) extendHash(alphabet []T, numbersHash int64, padded []T) {
+	if len(c.hash) < c.length {
+		i := (numbersHash + int64(c.hash[0])) % int64(len(c.guards))
+		c.hash = append(c.hash, 0)
+		copy(c.hash[1:], c.hash)
+		c.hash[0] = c.guards[i]
+
+		if len(c.hash) < c.length {
+			i := (numbersHash + int64(c.hash[2])) % int64(len(c.guards))
+			c.hash = append(c.hash, c.guards[i])
+		}
+	}
+
+	middle := len(alphabet) / 2
+	for len(c.hash) < c.length {
+		if padded != nil {
+			copy(alphabet, padded)
+			padded = nil
+		} else {
+			c.buf = append(c.buf[:0], alphabet...)
+			shuffleInPlace(alphabet, c.buf)
+		}
+
+		c.pad = append(c.pad[:0], alphabet[middle:]...)
+		c.pad = append(c.pad, c.hash...)
+		c.pad = append(c.pad, alphabet[:middle]...)
+		c.hash, c.pad = c.pad, c.hash
+
+		excess := len(c.hash) - c.length
+		if excess > 0 {
+			n := copy(c.hash, c.hash[excess/2:excess/2+c.length])
+			c.hash = c.hash[:n]
+		}
+	}
+}
+
+// decode input without prefix and append the numbers, verified tells whether
+// the input is exactly the hash of the numbers, which is checked structurally
+// instead of encoding the numbers once again
+func (c *codec[T]) decode(input string, numbers []int64) (_ []int64, verified bool, err error) {
+	var ok bool
+	if c.input, ok = appendSymbols(c.input[:0], input); !ok {
+		return numbers, false, fmt.Errorf("alphabet that was used for hashing was different")
+	}
+
+	if c.ordered != nil {
+		if c.maxLength > 0 && len(c.input) > c.maxLength {
+			return numbers, false, &LengthError{Length: len(c.input), MaxLength: c.maxLength}
+		}
+
+		numbers, err = c.ordered.decode(c.input, numbers)
+		return numbers, err == nil, err
+	}
+
+	core := c.breakdown(c.input)
+	if len(core) == 0 {
+		return numbers, false, nil
+	}
+
+	start := len(numbers)
+	lottery := core[0]
+	lotteryIndex := c.index.position(lottery)
+	breakdown := core[1:]
+	verified = true
+
+	var alphabet []T
+	for i := 0; ; i++ {
+		end := 0
+		for end < len(breakdown) && !c.sepSet.has(breakdown[end]) {
+			end++
+		}
+
+		group := breakdown[:end]
+
+		var positions []int32
+		alphabet, positions = c.step(i, alphabet, lottery, lotteryIndex)
+
+		var number int64
+		if positions != nil {
+			number, err = unhashWith(group, c.index, positions)
+		} else {
+			number, err = unhash(group, alphabet)
+		}
+
+		if err != nil {
+			return numbers, false, err
+		}
+
+		// the group must be exactly the hash of the number, which rules out
+		// empty groups, leading zeros and overflows, followed by the right separator
+		verified = verified && number >= 0 && isHashOf(group, number, alphabet)
+		if verified && end < len(breakdown) {
+			n := number % (int64(group[0]) + int64(i))
+			verified = breakdown[end] == c.seps[n%int64(len(c.seps))]
+		}
+
+		numbers = append(numbers, number)
+
+		if end == len(breakdown) {
+			break
+		}
+
+		breakdown = breakdown[end+1:]
+	}
+
+	if verified {
+		verified = c.verify(core, numbers[start:], alphabet, lotteryIndex)
+	}
+
+	if c.perm != nil {
+		for i := start; i < len(numbers); i++ {
+			if numbers[i], err = c.perm.backward(numbers[i]); err != nil {
+				return numbers[:start], false, err
+			}
+		}
+	}
+
+	return numbers, verified, nil
+}
+
+// verify lottery, guards and padding around the core, which is already known
+// to hold the numbers, alphabet is the one the last number was hashed with
+func (c *codec[T]) verify(core []T, numbers []int64, alphabet []T, lotteryIndex int) bool {
+	numbersHash := createNumbersHashInt(numbers)
+	if core[0] != c.alphabet[numbersHash%int64(len(c.alphabet))] {
+		return false
+	}
+
+	if c.maxLength > 0 && len(c.input) > c.maxLength {
+		return false
+	}
+
+	c.hash = append(c.hash[:0], core...)
+
+	// only padding shuffles the alphabet, which may come straight from the tables
+	if len(c.hash)+2 < c.length {
+		c.shuffled = append(c.shuffled[:0], alphabet...)
+		alphabet = c.shuffled
+	}
+
+	c.extendHash(alphabet, numbersHash, c.padded(len(numbers), lotteryIndex))
+
+	return equalSymbols(c.hash, c.input)
+}
+
+// breakdown - part of the input between guards, which holds lottery and numbers
+func (c *codec[T]) breakdown(input []T) []T {
+	guards, first, second := 0, len(input), len(input)
+	for i, s := range input {
+		if !c.guardSet.has(s) {
+			continue
+		}
+
+		switch guards {
+		case 0:
+			first = i
+		case 1:
+			second = i
+		}
+
+		guards++
+	}
+
+	if guards == 1 || guards == 2 {
+		if breakdown := input[first+1 : second]; len(breakdown) > 0 {
+			return breakdown
+		}
+	}
+
+	return input[:first]
+}
+
+// equals - whether c.hash is equal to s
+func (c *codec[T]) equals(s string) bool {
+	if hash, ok := any(c.hash).([]byte); ok {
+		return string(hash) == s
+	}
+
+	i := 0
+	for _, r := range s {
+		if i >= len(c.hash) || rune(c.hash[i]) != r {
+			return false
+		}
+		i++
+	}
+
+	return i == len(c.hash)
+}
+
+func (c *codec[T]) appendTo(dst []byte) []byte {
+	if hash, ok := any(c.hash).([]byte); ok {
+		return append(dst, hash...)
+	}
+
+	for _, s := range c.hash {
+		if s < utf8.RuneSelf {
+			dst = append(dst, byte(s))
+		} else {
+			dst = utf8.AppendRune(dst, rune(s))
+		}
+	}
+
+	return dst
+}
+
+func (c *codec[T]) String() string {
+	if hash, ok := any(c.hash).([]byte); ok {
+		return string(hash)
+	}
+
+	var sb strings.Builder
+	sb.Grow(len(c.hash))
+
+	for _, s := range c.hash {
+		if s < utf8.RuneSelf {
+			sb.WriteByte(byte(s))
+		} else {
+			sb.WriteRune(rune(s))
+		}
+	}
+
+	return sb.String()
+}
+
+func toSymbols[T symbol](in []rune) []T {
+	out := make([]T, len(in))
+	for i, r := range in {
+		out[i] = T(r)
+	}
+
+	return out
+}
+
+// appendSymbols of the string to dst, false if some rune does not fit into T
+func appendSymbols[T symbol](dst []T, s string) ([]T, bool) {
+	if b, ok := any(dst).([]byte); ok {
+		for i := 0; i < len(s); i++ {
+			if s[i] >= utf8.RuneSelf {
+				return dst, false
+			}
+		}
+
+		return any(append(b, s...)).([]T), true
+	}
+
+	for _, r := range s {
+		if rune(T(r)) != r {
+			return dst, false
+		}
+		dst = append(dst, T(r))
+	}
+
+	return dst, true
+}
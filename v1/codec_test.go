@@ -0,0 +1,330 @@
+package hashids
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newRuneHasher forces the rune codec even for ASCII only options
+func newRuneHasher(tb testing.TB, options Options) *Hasher {
+	h, err := New(options)
+	if err != nil {
+		tb.Fatal(err)
+	}
+
+	h.bytes = nil
+	h.runes = newCodec[rune](h.options)
+
+	return h
+}
+
+func Test_CodecIsChosenByAlphabet(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		options Options
+		ascii   bool
+	}{
+		{DefaultOptions("test salt"), true},
+		{Options{Salt: "test salt", Alphabet: "1234567890_!&*BAZ"}, true},
+		{Options{Salt: "test salt", Alphabet: "абвгдежзиклмнпрсто1234"}, false},
+		{Options{Salt: "соль"}, false},
+	}
+
+	for _, tc := range tt {
+		tc := tc
+
+		t.Run(tc.options.Alphabet+tc.options.Salt, func(t *testing.T) {
+			h, err := New(tc.options)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			assert.Equal(t, tc.ascii, h.bytes != nil)
+			assert.Equal(t, !tc.ascii, h.runes != nil)
+		})
+	}
+}
+
+func Test_ByteAndRuneCodecsProduceSameHashes(t *testing.T) {
+	t.Parallel()
+
+	options := []Options{
+		DefaultOptions("test salt"),
+		{Salt: "this is my salt", Length: 8},
+		{Salt: "my test salt", Length: 40, Prefix: "cus_"},
+		{Salt: "", Alphabet: LowercaseAlphabetWithDigits},
+		{Salt: "test salt", Alphabet: NumericAlphabet, Length: 12},
+		{Salt: "test salt", Alphabet: "cfhistuCFH", Length: 10},
+	}
+
+	inputs := [][]int64{
+		{0},
+		{1},
+		{1000},
+		{1, 2, 3},
+		{45, 434, 1313, 99},
+		{math.MaxInt64, 0, math.MaxInt64},
+	}
+
+	for i, o := range options {
+		byteHasher, err := New(o)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		runeHasher := newRuneHasher(t, o)
+
+		for _, input := range inputs {
+			t.Run(fmt.Sprintf("options %d input %v", i, input), func(t *testing.T) {
+				expected, err := runeHasher.Encode(input)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				actual, err := byteHasher.Encode(input)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				assert.Equal(t, expected, actual)
+
+				result, err := byteHasher.Decode(actual).Unwrap()
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				assert.Equal(t, input, result)
+			})
+		}
+	}
+}
+
+func Test_TablesAreSkippedForHugeAlphabets(t *testing.T) {
+	var sb strings.Builder
+	for r := '一'; r < '一'+2000; r++ {
+		sb.WriteRune(r)
+	}
+
+	h, err := New(Options{Salt: "test salt", Alphabet: sb.String()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Nil(t, h.runes.tables)
+
+	numbers := []int64{1, 123456789, 42}
+	hash, _ := h.Encode(numbers)
+	result, err := h.Decode(hash).Unwrap()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, numbers, result)
+
+	h, _ = New(DefaultOptions("test salt"))
+	assert.Equal(t, len(h.options.alphabet), len(h.bytes.tables))
+}
+
+func Test_SparseAlphabetsAreLookedUpInMap(t *testing.T) {
+	h, err := New(Options{Salt: "test salt", Alphabet: "абвгдеёжзийклмнопрстуфхцчшщъыьэюя😀😁😂😃😄😅😆😇😈😉😊😋😌😍😎😏"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.NotNil(t, h.runes.index.sparse)
+	assert.Equal(t, len(h.options.alphabet), len(h.runes.tables))
+
+	h, _ = New(DefaultOptions("test salt"))
+	assert.Nil(t, h.bytes.index.sparse)
+}
+
+func Test_StepsMatchShuffling(t *testing.T) {
+	t.Parallel()
+
+	numbers := make([]int64, 40)
+	for i := range numbers {
+		numbers[i] = int64(i * 7919)
+	}
+
+	for _, options := range equivalenceOptions {
+		options := options
+
+		t.Run(fmt.Sprintf("%q", options.Alphabet), func(t *testing.T) {
+			cached, _ := New(options)
+
+			// a full cache leaves every step but the first to shuffling
+			shuffling := cached.Clone()
+			if shuffling.bytes != nil {
+				shuffling.bytes.stepsSize = maxStepsSize
+			} else {
+				shuffling.runes.stepsSize = maxStepsSize
+			}
+
+			for i := 0; i < 2; i++ {
+				expected, _ := shuffling.Encode(numbers)
+				hash, _ := cached.Encode(numbers)
+				assert.Equal(t, expected, hash)
+
+				result, err := cached.Decode(hash).Unwrap()
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				assert.Equal(t, numbers, result)
+			}
+		})
+	}
+}
+
+func Test_ByteCodecRejectsNonASCIIInput(t *testing.T) {
+	h, _ := New(DefaultOptions("test salt"))
+
+	hash, _ := h.Encode(1)
+
+	// 'š' would turn into 'a' if truncated to a byte
+	_, err := h.Decode(hash[:3] + "š" + hash[4:]).Unwrap()
+	assert.Equal(t, "alphabet that was used for hashing was different", err.Error())
+}
+
+func Benchmark_EncodeBytes(b *testing.B) {
+	h, _ := New(DefaultOptions("test salt"))
+	dst := make([]byte, 0, 64)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		dst, _ = h.AppendEncode(dst[:0], int64(i), 100, 500)
+	}
+}
+
+func Benchmark_EncodeRunes(b *testing.B) {
+	h := newRuneHasher(b, DefaultOptions("test salt"))
+	dst := make([]byte, 0, 64)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		dst, _ = h.AppendEncode(dst[:0], int64(i), 100, 500)
+	}
+}
+
+func Benchmark_DecodeBytes(b *testing.B) {
+	h, _ := New(DefaultOptions("test salt"))
+	hash, _ := h.Encode(123456789, 100, 500)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		h.Decode(hash)
+	}
+}
+
+func Benchmark_DecodeRunes(b *testing.B) {
+	h := newRuneHasher(b, DefaultOptions("test salt"))
+	hash, _ := h.Encode(123456789, 100, 500)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		h.Decode(hash)
+	}
+}
+
+func Benchmark_DecodeRuneAlphabet(b *testing.B) {
+	h, _ := New(Options{Salt: "test salt", Alphabet: "абвгдеёжзийклмнопрстуфхцчшщъыьэюя😀😁😂😃😄😅😆😇😈😉😊😋😌😍😎😏"})
+	hash, _ := h.Encode(123456789, 100, 500, 987654321, 42)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		h.Decode(hash)
+	}
+}
+
+var equivalenceOptions = []Options{
+	DefaultOptions("test salt"),
+	{Salt: "this is my salt", Length: 8},
+	{Salt: "", Alphabet: LowercaseAlphabetWithDigits},
+	{Salt: "test salt", Alphabet: NumericAlphabet, Length: 12},
+	{Salt: "test salt", Alphabet: "cfhistuCFH", Length: 10},
+	{Salt: "this is test salt", Alphabet: "98АБВГДЕжзиклмнпрсто1234", Length: 8},
+	{Salt: "test salt", Alphabet: "😀😁😂😃😄😅😆😇😈😉😊😋😌😍😎😏", Length: 6},
+}
+
+// mutations of a valid hash which are likely to hit all the decoding branches
+func mutations(o Options, hash string, rnd *rand.Rand) []string {
+	symbols := []rune(hash)
+	charset := append(append(append([]rune{}, o.alphabet...), o.seps...), o.guards...)
+	charset = append(charset, '#', 'я')
+
+	out := []string{hash, "", hash[:len(hash)/2], hash + hash}
+	for i := 0; i < 20; i++ {
+		mutated := append([]rune{}, symbols...)
+		switch rnd.Intn(3) {
+		case 0:
+			mutated[rnd.Intn(len(mutated))] = charset[rnd.Intn(len(charset))]
+		case 1:
+			at := rnd.Intn(len(mutated) + 1)
+			mutated = append(mutated[:at], append([]rune{charset[rnd.Intn(len(charset))]}, mutated[at:]...)...)
+		case 2:
+			at := rnd.Intn(len(mutated))
+			mutated = append(mutated[:at], mutated[at+1:]...)
+		}
+		out = append(out, string(mutated))
+	}
+
+	random := make([]rune, rnd.Intn(12))
+	for i := range random {
+		random[i] = charset[rnd.Intn(len(charset))]
+	}
+
+	return append(out, string(random))
+}
+
+func Test_DecodeIsEquivalentToReference(t *testing.T) {
+	t.Parallel()
+
+	rnd := rand.New(rand.NewSource(42))
+
+	for i, o := range equivalenceOptions {
+		h, err := New(o)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		t.Run(fmt.Sprintf("options %d", i), func(t *testing.T) {
+			for j := 0; j < 300; j++ {
+				input := make([]int64, 1+rnd.Intn(4))
+				for k := range input {
+					input[k] = rnd.Int63n(1 << uint(rnd.Intn(62)+1))
+				}
+
+				hash, err := h.Encode(input)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				reference, err := referenceEncode(h.options, input)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				assert.Equal(t, reference, hash)
+
+				for _, m := range mutations(h.options, hash, rnd) {
+					expected, expectedErr := referenceDecode(h.options, m)
+					actual, actualErr := h.Decode(m).Unwrap()
+
+					assert.Equal(t, expected, actual, m)
+					assert.Equal(t, expectedErr == nil, actualErr == nil, m)
+
+					// byte codec rejects non ASCII input straight away
+					if h.runes != nil || !strings.ContainsRune(m, 'я') {
+						assert.Equal(t, fmt.Sprint(expectedErr), fmt.Sprint(actualErr), m)
+					}
+				}
+			}
+		})
+	}
+}
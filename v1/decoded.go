@@ -10,8 +10,9 @@ type ResultMapFunc func(v int64, i int) int64
 
 // DecodedResult of the hash input
 type DecodedResult struct {
-	numbers []int64
-	err     error
+	numbers    []int64
+	err        error
+	timestamps timestamps
 }
 
 // NewDecodedResult result
@@ -93,7 +94,8 @@ func (d DecodedResult) Int64Slice() ([]int64, error) {
 	return d.Unwrap()
 }
 
-// AsTime transform result into time object and return it
+// AsTime transform result into time object and return it,
+// TimePrecision, Epoch and Location of the hasher are taken into account
 func (d DecodedResult) AsTime() (time.Time, error) {
 	if d.err != nil {
 		return time.Unix(0, 0), d.err
@@ -103,9 +105,7 @@ func (d DecodedResult) AsTime() (time.Time, error) {
 		return time.Unix(0, 0), fmt.Errorf("valid timestamp must be contained in a int64 slice as single value, got %v", d.numbers)
 	}
 
-	t := time.Unix(0, d.numbers[0])
-
-	return t, nil
+	return d.timestamps.time(d.numbers[0])
 }
 
 // AsHex returns result converted to hexidecimal format
@@ -124,5 +124,5 @@ func (d DecodedResult) Map(f ResultMapFunc) DecodedResult {
 		result[i] = f(v, i)
 	}
 
-	return DecodedResult{result, d.err}
+	return DecodedResult{result, d.err, d.timestamps}
 }
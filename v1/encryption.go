@@ -0,0 +1,109 @@
+package hashids
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/binary"
+	"fmt"
+	"math/bits"
+)
+
+// feistelRounds - as many as FF1 uses
+const feistelRounds = 10
+
+// feistel - format preserving encryption of numbers from 0 to domain-1,
+// a balanced Feistel network with AES as the round function over the smallest
+// even count of bits that covers the domain, values outside of the domain
+// are encrypted again until they fall into it, which is called cycle walking
+type feistel struct {
+	block  cipher.Block
+	domain uint64
+	half   uint
+	in     [aes.BlockSize]byte
+	out    [aes.BlockSize]byte
+}
+
+// newFeistel with AES-128, 192 or 256 key, domain 0 means all of int64
+func newFeistel(key []byte, domain uint64) (*feistel, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Key: %v", err)
+	}
+
+	if domain == 0 {
+		domain = 1 << 63
+	}
+
+	half := uint(bits.Len64(domain-1)+1) / 2
+	if half == 0 {
+		half = 1
+	}
+
+	return &feistel{block: block, domain: domain, half: half}, nil
+}
+
+func (f *feistel) clone() permutation {
+	return &feistel{block: f.block, domain: f.domain, half: f.half}
+}
+
+func (f *feistel) forward(v int64) (int64, error) {
+	if uint64(v) >= f.domain {
+		return 0, outOfDomain(v, f.domain)
+	}
+
+	x := uint64(v)
+	for {
+		x = f.encrypt(x)
+		if x < f.domain {
+			return int64(x), nil
+		}
+	}
+}
+
+func (f *feistel) backward(v int64) (int64, error) {
+	if v < 0 || uint64(v) >= f.domain {
+		return 0, fmt.Errorf("number %d was not encrypted with the Key", v)
+	}
+
+	x := uint64(v)
+	for {
+		x = f.decrypt(x)
+		if x < f.domain {
+			return int64(x), nil
+		}
+	}
+}
+
+func (f *feistel) encrypt(x uint64) uint64 {
+	mask := uint64(1)<<f.half - 1
+	l, r := x>>f.half, x&mask
+
+	for i := 0; i < feistelRounds; i++ {
+		l, r = r, l^f.round(i, r)&mask
+	}
+
+	return l<<f.half | r
+}
+
+func (f *feistel) decrypt(x uint64) uint64 {
+	mask := uint64(1)<<f.half - 1
+	l, r := x>>f.half, x&mask
+
+	for i := feistelRounds - 1; i >= 0; i-- {
+		l, r = r^f.round(i, l)&mask, l
+	}
+
+	return l<<f.half | r
+}
+
+// round function of the round i, the domain is a tweak,
+// so different MaxLength gives unrelated permutations
+func (f *feistel) round(i int, x uint64) uint64 {
+	// halves are at most 32 bits
+	f.in[0] = byte(i)
+	binary.BigEndian.PutUint32(f.in[1:5], uint32(x))
+	binary.BigEndian.PutUint64(f.in[8:], f.domain)
+	f.block.Encrypt(f.out[:], f.in[:])
+
+	return binary.BigEndian.Uint64(f.out[:8])
+}
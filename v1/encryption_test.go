@@ -0,0 +1,163 @@
+package hashids
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testKey = []byte("0123456789abcdef")
+
+func Test_EncryptedRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		name    string
+		options Options
+	}{
+		{"aes-128", Options{Salt: "key salt", Key: testKey}},
+		{"aes-256", Options{Salt: "key salt", Key: []byte("0123456789abcdef0123456789abcdef"), Length: 12}},
+		{"numeric", Options{Salt: "key salt", Key: testKey, Alphabet: NumericAlphabet}},
+		{"unicode", Options{Salt: "key salt", Key: testKey, Alphabet: "абвгдежзийклмнопрстуфхцчшщ0123456789"}},
+		{"max length", Options{Salt: "key salt", Key: testKey, Length: 6, MaxLength: 6}},
+	}
+
+	for _, tc := range tt {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			h, err := New(tc.options)
+			assert.NoError(t, err)
+
+			plain := tc.options
+			plain.Key = nil
+			p, _ := New(plain)
+
+			rnd := rand.New(rand.NewSource(1))
+			for i := 0; i < 500; i++ {
+				numbers := []int64{rnd.Int63n(h.MaxEncodableValue())}
+				if tc.options.MaxLength == 0 {
+					numbers = append(numbers, int64(i))
+				}
+
+				hash, err := h.Encode(numbers)
+				assert.NoError(t, err)
+
+				// the hash is the plain hash of the encrypted numbers
+				encrypted := make([]int64, len(numbers))
+				for j, n := range numbers {
+					encrypted[j], _ = h.permutation().forward(n)
+				}
+
+				expected, _ := p.Encode(encrypted)
+				assert.Equal(t, expected, hash)
+
+				decoded, err := h.Decode(hash).Unwrap()
+				assert.NoError(t, err)
+				assert.Equal(t, numbers, decoded)
+			}
+		})
+	}
+}
+
+func Test_EncryptionIsAPermutationOfTheDomain(t *testing.T) {
+	t.Parallel()
+
+	for _, domain := range []uint64{1, 2, 10, 1000, 4096} {
+		f, err := newFeistel(testKey, domain)
+		assert.NoError(t, err)
+
+		seen := make(map[int64]bool)
+		for v := int64(0); v < int64(domain); v++ {
+			e, err := f.forward(v)
+			assert.NoError(t, err)
+			assert.True(t, e >= 0 && uint64(e) < domain)
+			assert.False(t, seen[e])
+			seen[e] = true
+
+			d, err := f.backward(e)
+			assert.NoError(t, err)
+			assert.Equal(t, v, d)
+		}
+	}
+}
+
+func Test_EncryptedHashesFitMaxLength(t *testing.T) {
+	t.Parallel()
+
+	h, _ := New(Options{Salt: "key salt", Key: testKey, Length: 4, MaxLength: 4})
+
+	maxValue := h.MaxEncodableValue()
+	assert.Equal(t, int64(math.Pow(float64(len(h.options.alphabet)), 3))-1, maxValue)
+
+	for n := maxValue - 1000; n <= maxValue; n++ {
+		hash, err := h.Encode(n)
+		assert.NoError(t, err)
+		assert.Equal(t, 4, len(hash))
+	}
+
+	_, err := h.Encode(maxValue + 1)
+	assert.EqualError(t, err, "number 85184 does not fit into MaxLength, the largest is 85183")
+}
+
+func Test_EncryptionHidesSequences(t *testing.T) {
+	t.Parallel()
+
+	h, _ := New(Options{Salt: "key salt", Key: testKey, Length: 8})
+	p, _ := New(Options{Salt: "key salt", Length: 8})
+
+	a, _ := h.Encode(1)
+	b, _ := p.Encode(1)
+	assert.NotEqual(t, a, b)
+
+	other, _ := New(Options{Salt: "key salt", Key: []byte("fedcba9876543210"), Length: 8})
+	n, err := other.Decode(a).FirstInt64()
+	assert.NoError(t, err)
+	assert.NotEqual(t, int64(1), n)
+
+	stats, err := h.Stats(1, 1000)
+	assert.NoError(t, err)
+	total := int64(0)
+	for _, count := range stats.Counts {
+		total += count
+	}
+	assert.Equal(t, int64(1000), total)
+
+	_, err = h.Stats(0, 1<<20)
+	assert.EqualError(t, err, "range 0..1048576 is too large, at most 65536 permuted numbers are counted")
+}
+
+func Test_InvalidKey(t *testing.T) {
+	t.Parallel()
+
+	_, err := New(Options{Key: []byte("short")})
+	assert.EqualError(t, err, "invalid Key: crypto/aes: invalid key size 5")
+
+	_, err = New(Options{Key: testKey, OrderPreserving: true})
+	assert.EqualError(t, err, "Key cannot be used in order preserving mode")
+}
+
+func Test_EncryptedAppendEncodeDoesNotAllocate(t *testing.T) {
+	h, _ := New(Options{Salt: "key salt", Key: testKey})
+	dst := make([]byte, 0, 64)
+
+	allocs := testing.AllocsPerRun(100, func() {
+		dst, _ = h.AppendEncode(dst[:0], 1, 2, 3, 1000000)
+	})
+
+	assert.Equal(t, float64(0), allocs)
+}
+
+func Benchmark_EncodeEncrypted(b *testing.B) {
+	h, _ := New(Options{Salt: "key salt", Key: testKey, Length: 16})
+	dst := make([]byte, 0, 64)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		dst, _ = h.AppendEncode(dst[:0], int64(i))
+	}
+}
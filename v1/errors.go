@@ -0,0 +1,32 @@
+package hashids
+
+import "fmt"
+
+// LengthError - hash would exceed the maximum length
+type LengthError struct {
+	Length    int
+	MaxLength int
+}
+
+// Error message
+func (e *LengthError) Error() string {
+	return fmt.Sprintf("hash length %d exceeds maximum length of %d", e.Length, e.MaxLength)
+}
+
+// LineError - error of a single line of a stream, the rest of the stream
+// is processed anyway
+type LineError struct {
+	Line  int
+	Input string
+	Err   error
+}
+
+// Error message
+func (e *LineError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+// Unwrap the underlying error
+func (e *LineError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,89 @@
+package hashids
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+// DefaultExpiryPrecision of expiry timestamps
+const DefaultExpiryPrecision = time.Second
+
+// ErrExpired - matches every *ExpiredError with errors.Is
+var ErrExpired = errors.New("hash has expired")
+
+// ExpiredError - hash has expired at ExpiredAt
+type ExpiredError struct {
+	ExpiredAt time.Time
+}
+
+// Error message
+func (e *ExpiredError) Error() string {
+	return fmt.Sprintf("hash has expired at %s", e.ExpiredAt.Format(time.RFC3339))
+}
+
+// Is ErrExpired
+func (e *ExpiredError) Is(target error) bool {
+	return target == ErrExpired
+}
+
+// EncodeWithExpiry - hash of the numbers valid for at least ttl from now, the
+// expiry counts ExpiryPrecision units since Epoch rounded up and is appended
+// as the last number.
+//
+// ATTENTION: without Secret anybody can decode the hash and encode
+// the same numbers with a later expiry, only Secret makes it tamper proof.
+func (h *Hasher) EncodeWithExpiry(ttl time.Duration, numbers ...int64) (string, error) {
+	if ttl <= 0 {
+		return "", fmt.Errorf("ttl must be positive, %s given", ttl)
+	}
+
+	now := h.now()
+
+	expiry, rest, err := h.options.expiryTimestamps().units(now.Add(ttl))
+	if err != nil {
+		return "", fmt.Errorf("invalid expiry: %v", err)
+	}
+
+	if rest {
+		if expiry == math.MaxInt64 {
+			return "", fmt.Errorf("invalid expiry: %s is too far from the epoch", now.Add(ttl).Format(time.RFC3339))
+		}
+
+		expiry++
+	}
+
+	return h.Encode(append(append(make([]int64, 0, len(numbers)+1), numbers...), expiry))
+}
+
+// DecodeValid decodes a hash of EncodeWithExpiry, the result does not contain
+// the expiry, it has an *ExpiredError if the hash has expired by now,
+// without Secret the expiry may have been forged
+func (h *Hasher) DecodeValid(input string, now time.Time) *DecodedResult {
+	numbers, err := h.Decode(input).Unwrap()
+	if err != nil {
+		return h.result(nil, err)
+	}
+
+	if len(numbers) == 0 {
+		return h.result(nil, fmt.Errorf("hash has no expiry"))
+	}
+
+	expiry := numbers[len(numbers)-1]
+
+	expiresAt, err := h.options.expiryTimestamps().time(expiry)
+	if err != nil {
+		return h.result(nil, fmt.Errorf("hash has invalid expiry %d", expiry))
+	}
+	if !now.Before(expiresAt) {
+		return h.result(nil, &ExpiredError{ExpiredAt: expiresAt})
+	}
+
+	return h.result(numbers[:len(numbers)-1], nil)
+}
+
+// expiryTimestamps count ExpiryPrecision units since Epoch, expiry is in UTC
+func (o Options) expiryTimestamps() timestamps {
+	return timestamps{precision: o.ExpiryPrecision, epoch: o.Epoch}
+}
@@ -0,0 +1,146 @@
+package hashids
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_EncodeWithExpiry(t *testing.T) {
+	t.Parallel()
+
+	h, _ := New(Options{Salt: "expiry salt", Secret: testSecret})
+
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	h.now = func() time.Time { return now }
+
+	hash, err := h.EncodeWithExpiry(time.Hour, 7, 42)
+	assert.NoError(t, err)
+
+	numbers, err := h.DecodeValid(hash, now).Unwrap()
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{7, 42}, numbers)
+
+	numbers, err = h.DecodeValid(hash, now.Add(time.Hour-time.Nanosecond)).Unwrap()
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{7, 42}, numbers)
+
+	err = h.DecodeValid(hash, now.Add(time.Hour)).Err()
+	assert.True(t, errors.Is(err, ErrExpired))
+
+	var expired *ExpiredError
+	assert.True(t, errors.As(err, &expired))
+	assert.Equal(t, now.Add(time.Hour), expired.ExpiredAt)
+	assert.Equal(t, "hash has expired at 2026-10-19T13:00:00Z", err.Error())
+
+	// without numbers the hash is just an expiring token
+	token, err := h.EncodeWithExpiry(time.Minute)
+	assert.NoError(t, err)
+
+	numbers, err = h.DecodeValid(token, now).Unwrap()
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(numbers))
+}
+
+func Test_EncodeWithExpiryIsValidFromNow(t *testing.T) {
+	t.Parallel()
+
+	h, _ := New(Options{Salt: "expiry salt"})
+
+	hash, err := h.EncodeWithExpiry(time.Hour, 7)
+	assert.NoError(t, err)
+
+	numbers, err := h.Clone().DecodeValid(hash, time.Now()).Unwrap()
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{7}, numbers)
+
+	assert.True(t, errors.Is(h.DecodeValid(hash, time.Now().Add(2*time.Hour)).Err(), ErrExpired))
+}
+
+func Test_ExpiryPrecision(t *testing.T) {
+	t.Parallel()
+
+	seconds, _ := New(Options{Salt: "expiry salt"})
+	minutes, err := New(Options{Salt: "expiry salt", ExpiryPrecision: time.Minute})
+	assert.NoError(t, err)
+
+	now := time.Date(2026, 10, 19, 12, 0, 30, 0, time.UTC)
+	seconds.now = func() time.Time { return now }
+	minutes.now = func() time.Time { return now }
+
+	a, _ := seconds.EncodeWithExpiry(time.Hour, 1)
+	b, _ := minutes.EncodeWithExpiry(time.Hour, 1)
+	assert.Less(t, len(b), len(a))
+
+	// the expiry is rounded up to 13:01
+	assert.NoError(t, minutes.DecodeValid(b, now.Add(time.Hour)).Err())
+	assert.True(t, errors.Is(minutes.DecodeValid(b, now.Add(time.Hour+30*time.Second)).Err(), ErrExpired))
+
+	_, err = New(Options{ExpiryPrecision: -time.Second})
+	assert.EqualError(t, err, "ExpiryPrecision cannot be negative")
+}
+
+func Test_ExpiryCannotBeForged(t *testing.T) {
+	t.Parallel()
+
+	h, _ := New(Options{Salt: "expiry salt", Secret: testSecret})
+	plain, _ := New(Options{Salt: "expiry salt"})
+
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	h.now = func() time.Time { return now }
+
+	hash, _ := h.EncodeWithExpiry(time.Minute, 7)
+	numbers, _ := h.Decode(hash).Unwrap()
+
+	// the same numbers with a later expiry, the tag cannot be recomputed without the secret
+	untagged, _ := plain.Encode(numbers[0], numbers[1]+3600)
+	forged := untagged + hash[len(hash)-DefaultTagLength:]
+
+	err := h.DecodeValid(forged, now.Add(time.Hour)).Err()
+	assert.True(t, errors.Is(err, ErrTagMismatch))
+}
+
+func Test_ExpiryErrors(t *testing.T) {
+	t.Parallel()
+
+	h, _ := New(Options{Salt: "expiry salt"})
+
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	h.now = func() time.Time { return now }
+
+	_, err := h.EncodeWithExpiry(0, 1)
+	assert.EqualError(t, err, "ttl must be positive, 0s given")
+
+	_, err = h.EncodeWithExpiry(time.Hour, -1)
+	assert.EqualError(t, err, "negative numbers like -1 are not allowed")
+
+	old, _ := h.Encode(5)
+	assert.True(t, errors.Is(h.DecodeValid(old, now).Err(), ErrExpired))
+
+	assert.Error(t, h.DecodeValid("invalid", now).Err())
+}
+
+func Test_ExpiringResultsKeepTimestamps(t *testing.T) {
+	t.Parallel()
+
+	berlin := time.FixedZone("CET", 3600)
+	h, _ := New(Options{Salt: "expiry salt", TimePrecision: time.Second, Location: berlin})
+
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	timestamp, _ := h.options.timestamps().number(now)
+	h.now = func() time.Time { return now }
+
+	hash, _ := h.EncodeWithExpiry(time.Hour, timestamp)
+	decoded, err := h.DecodeValid(hash, now).AsTime()
+	assert.NoError(t, err)
+	assert.Equal(t, now.In(berlin), decoded)
+
+	p, _ := NewPacking(40)
+	ph := h.WithPacking(p)
+	packed, _ := ph.Encode(timestamp)
+	decoded, err = ph.Decode(packed).AsTime()
+	assert.NoError(t, err)
+	assert.Equal(t, now.In(berlin), decoded)
+}
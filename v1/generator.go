@@ -0,0 +1,183 @@
+package hashids
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultNodeBits of generated ids, up to 1024 nodes
+	DefaultNodeBits = 10
+	// DefaultSequenceBits of generated ids, up to 4096 ids per tick and node
+	DefaultSequenceBits = 12
+	// DefaultTick of generated ids
+	DefaultTick = time.Millisecond
+
+	// maxGeneratorBits - at least 23 bits are left for the time
+	maxGeneratorBits = 40
+)
+
+// GeneratorOptions of Snowflake style ids, only Epoch and Location
+// of the hasher apply to them, its TimePrecision does not
+type GeneratorOptions struct {
+	// Node or worker id, unique among the generators of the same ids
+	Node int64
+	// NodeBits of the node id, 0 for a single node
+	NodeBits int
+	// SequenceBits of the sequence within a tick, 0 for a single id per tick
+	SequenceBits int
+	// Tick of the time, it replaces TimePrecision of the hasher, DefaultTick when 0
+	Tick time.Duration
+}
+
+// DefaultGeneratorOptions of the node
+func DefaultGeneratorOptions(node int64) GeneratorOptions {
+	return GeneratorOptions{
+		Node:         node,
+		NodeBits:     DefaultNodeBits,
+		SequenceBits: DefaultSequenceBits,
+		Tick:         DefaultTick,
+	}
+}
+
+// GeneratedID - parts of an id of a Generator
+type GeneratedID struct {
+	ID       int64
+	Time     time.Time
+	Node     int64
+	Sequence int64
+}
+
+// Generator of unique ids, each is made of the ticks since the epoch, the node
+// and a sequence number within the tick, like Twitter's Snowflake, and hashed
+// with a copy of the hasher, ids of a generator only grow, it is safe for
+// concurrent use
+type Generator struct {
+	mu sync.Mutex
+	h  *Hasher
+
+	node         int64
+	nodeBits     uint
+	sequenceBits uint
+	timestamps   timestamps
+	now          func() time.Time
+
+	lastTick int64
+	sequence int64
+}
+
+// NewGenerator of ids hashed by h
+func NewGenerator(h *Hasher, options GeneratorOptions) (*Generator, error) {
+	if options.Tick == 0 {
+		options.Tick = DefaultTick
+	}
+
+	if options.NodeBits < 0 || options.SequenceBits < 0 || options.NodeBits+options.SequenceBits > maxGeneratorBits {
+		return nil, fmt.Errorf("NodeBits and SequenceBits cannot be negative and take at most %d bits together", maxGeneratorBits)
+	}
+
+	if options.Tick < 0 {
+		return nil, fmt.Errorf("Tick cannot be negative")
+	}
+
+	if options.Node < 0 || options.Node >= 1<<uint(options.NodeBits) {
+		return nil, fmt.Errorf("Node must be from 0 to %d", 1<<uint(options.NodeBits)-1)
+	}
+
+	return &Generator{
+		h:            h.Clone(),
+		node:         options.Node,
+		nodeBits:     uint(options.NodeBits),
+		sequenceBits: uint(options.SequenceBits),
+		timestamps:   timestamps{precision: options.Tick, epoch: h.options.Epoch, location: h.options.Location},
+		now:          time.Now,
+		lastTick:     -1,
+	}, nil
+}
+
+// Next hash
+func (g *Generator) Next() (string, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	id, err := g.next()
+	if err != nil {
+		return "", err
+	}
+
+	return g.h.Encode(id)
+}
+
+// NextID without hashing it
+func (g *Generator) NextID() (int64, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return g.next()
+}
+
+// next id, when the sequence of a tick is exhausted or the clock goes back
+// the ticks are taken ahead of the clock, so ids never repeat
+func (g *Generator) next() (int64, error) {
+	tick, err := g.timestamps.number(g.now())
+	if err != nil {
+		return 0, err
+	}
+
+	if tick <= g.lastTick {
+		tick = g.lastTick
+		g.sequence++
+
+		if g.sequence == 1<<g.sequenceBits {
+			tick++
+			g.sequence = 0
+		}
+	} else {
+		g.sequence = 0
+	}
+
+	if tick >= 1<<(63-g.nodeBits-g.sequenceBits) {
+		return 0, fmt.Errorf("ticks since the epoch do not fit into %d bits", 63-g.nodeBits-g.sequenceBits)
+	}
+
+	g.lastTick = tick
+
+	return tick<<(g.nodeBits+g.sequenceBits) | g.node<<g.sequenceBits | g.sequence, nil
+}
+
+// Decode the hash of a generated id into its parts
+func (g *Generator) Decode(hash string) (GeneratedID, error) {
+	g.mu.Lock()
+	numbers, err := g.h.Decode(hash).Unwrap()
+	g.mu.Unlock()
+
+	if err != nil {
+		return GeneratedID{}, err
+	}
+
+	if len(numbers) != 1 {
+		return GeneratedID{}, fmt.Errorf("generated id must be a single number, got %d", len(numbers))
+	}
+
+	return g.Parts(numbers[0])
+}
+
+// Parts of the id
+func (g *Generator) Parts(id int64) (GeneratedID, error) {
+	if id < 0 {
+		return GeneratedID{}, fmt.Errorf("generated id cannot be negative")
+	}
+
+	t, err := g.timestamps.time(id >> (g.nodeBits + g.sequenceBits))
+	if err != nil {
+		return GeneratedID{}, err
+	}
+
+	return GeneratedID{
+		ID:       id,
+		Time:     t,
+		Node:     id >> g.sequenceBits & (1<<g.nodeBits - 1),
+		Sequence: id & (1<<g.sequenceBits - 1),
+	}, nil
+}
@@ -0,0 +1,154 @@
+package hashids
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestGenerator(t *testing.T, node int64, now func() time.Time) *Generator {
+	h, _ := New(Options{Salt: "generator salt", Epoch: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)})
+
+	g, err := NewGenerator(h, DefaultGeneratorOptions(node))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if now != nil {
+		g.now = now
+	}
+
+	return g
+}
+
+func Test_GeneratorParts(t *testing.T) {
+	t.Parallel()
+
+	moment := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	g := newTestGenerator(t, 7, func() time.Time { return moment })
+
+	first, err := g.Next()
+	assert.NoError(t, err)
+
+	second, err := g.Next()
+	assert.NoError(t, err)
+	assert.NotEqual(t, first, second)
+
+	parts, err := g.Decode(second)
+	assert.NoError(t, err)
+	assert.Equal(t, moment, parts.Time)
+	assert.Equal(t, int64(7), parts.Node)
+	assert.Equal(t, int64(1), parts.Sequence)
+
+	// the hash is the one of the hasher
+	h, _ := New(Options{Salt: "generator salt"})
+	id, err := h.Decode(second).FirstInt64()
+	assert.NoError(t, err)
+	assert.Equal(t, parts.ID, id)
+}
+
+func Test_GeneratorOfSingleNode(t *testing.T) {
+	t.Parallel()
+
+	moment := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	h, _ := New(Options{Salt: "generator salt", TimePrecision: time.Nanosecond})
+
+	g, err := NewGenerator(h, GeneratorOptions{SequenceBits: 4, Tick: time.Second})
+	assert.NoError(t, err)
+	g.now = func() time.Time { return moment }
+
+	id, err := g.NextID()
+	assert.NoError(t, err)
+
+	parts, err := g.Parts(id)
+	assert.NoError(t, err)
+	assert.Equal(t, GeneratedID{ID: id, Time: moment, Node: 0, Sequence: 0}, parts)
+	assert.Equal(t, moment.Unix()<<4, id)
+}
+
+func Test_GeneratorIsMonotonic(t *testing.T) {
+	t.Parallel()
+
+	moment := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	clock := []time.Time{moment, moment, moment.Add(-time.Second), moment.Add(time.Millisecond)}
+	calls := 0
+
+	g := newTestGenerator(t, 1, func() time.Time {
+		if calls < len(clock) {
+			calls++
+			return clock[calls-1]
+		}
+
+		// the clock is stuck, so the sequence is exhausted
+		return moment.Add(time.Millisecond)
+	})
+
+	prev := int64(-1)
+	for i := 0; i < 3*(1<<DefaultSequenceBits); i++ {
+		id, err := g.NextID()
+		assert.NoError(t, err)
+		assert.True(t, id > prev)
+		prev = id
+	}
+
+	parts, _ := g.Parts(prev)
+	assert.True(t, parts.Time.After(moment.Add(time.Millisecond)))
+}
+
+func Test_GeneratorIsUniqueAcrossGoroutinesAndNodes(t *testing.T) {
+	t.Parallel()
+
+	generators := []*Generator{newTestGenerator(t, 1, nil), newTestGenerator(t, 2, nil)}
+
+	var mu sync.Mutex
+	seen := make(map[string]bool)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(g *Generator) {
+			defer wg.Done()
+
+			for j := 0; j < 1000; j++ {
+				hash, err := g.Next()
+				assert.NoError(t, err)
+
+				mu.Lock()
+				assert.False(t, seen[hash])
+				seen[hash] = true
+				mu.Unlock()
+			}
+		}(generators[i%2])
+	}
+
+	wg.Wait()
+	assert.Equal(t, 8000, len(seen))
+}
+
+func Test_GeneratorErrors(t *testing.T) {
+	t.Parallel()
+
+	h, _ := New(Options{Salt: "generator salt"})
+
+	_, err := NewGenerator(h, DefaultGeneratorOptions(1024))
+	assert.EqualError(t, err, "Node must be from 0 to 1023")
+
+	_, err = NewGenerator(h, GeneratorOptions{Node: 1})
+	assert.EqualError(t, err, "Node must be from 0 to 0")
+
+	_, err = NewGenerator(h, GeneratorOptions{NodeBits: 30, SequenceBits: 20})
+	assert.EqualError(t, err, "NodeBits and SequenceBits cannot be negative and take at most 40 bits together")
+
+	_, err = NewGenerator(h, GeneratorOptions{NodeBits: -1})
+	assert.EqualError(t, err, "NodeBits and SequenceBits cannot be negative and take at most 40 bits together")
+
+	g := newTestGenerator(t, 0, func() time.Time { return time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC) })
+	_, err = g.Next()
+	assert.EqualError(t, err, "time 2023-01-01T00:00:00Z is before the epoch 2024-01-01T00:00:00Z")
+
+	pair, _ := h.Encode(1, 2)
+	_, err = g.Decode(pair)
+	assert.EqualError(t, err, "generated id must be a single number, got 2")
+}
@@ -12,8 +12,16 @@ type Hasher struct {
 	maxLengthPerNumber int
 
 	numbers []int64
-	hash    []rune
-	buf     []rune
+	tag     []byte
+	// tagger of hashes when Secret is set
+	tagger *tagger
+
+	// only one of the codecs is set, bytes for ASCII only configurations
+	bytes *codec[byte]
+	runes *codec[rune]
+
+	// now is the clock of EncodeWithExpiry
+	now func() time.Time
 }
 
 // New obfuscator
@@ -25,19 +33,58 @@ func New(options Options) (*Hasher, error) {
 
 	h := &Hasher{
 		options: options,
+		now:     time.Now,
+	}
+
+	if options.isASCII() {
+		h.bytes = newCodec[byte](options)
+	} else {
+		h.runes = newCodec[rune](options)
 	}
 
 	// Calculate the maximum possible string length by hashing the maximum possible id
-	encoded, err := h.Encode(math.MaxInt64)
-	if err != nil {
+	h.reset()
+	if err := h.hashNumbers([]int64{math.MaxInt64}); err != nil {
 		return nil, fmt.Errorf("unable to encode maximum int64 to find max encoded value length: %s", err)
 	}
 
-	h.maxLengthPerNumber = len(encoded)
+	h.maxLengthPerNumber = h.hashLen()
+
+	if options.Secret != nil {
+		h.tagger = newTagger(options.Secret, options.alphabet, options.TagLength)
+	}
+
+	// numbers are permuted only now, since the largest of them may be out of
+	// the domain, the hash of a permuted number is never longer anyway
+	perm, err := options.permutation()
+	if err != nil {
+		return nil, err
+	}
+
+	if perm != nil {
+		h.setPermutation(perm)
+	}
 
 	return h, nil
 }
 
+func (h *Hasher) setPermutation(p permutation) {
+	if h.bytes != nil {
+		h.bytes.perm = p
+	} else {
+		h.runes.perm = p
+	}
+}
+
+// permutation of the numbers, nil if they are hashed as they are
+func (h *Hasher) permutation() permutation {
+	if h.bytes != nil {
+		return h.bytes.perm
+	}
+
+	return h.runes.perm
+}
+
 // SetPrefix explicitly
 func (h *Hasher) SetPrefix(prefix string) *Hasher {
 	h.options.Prefix = prefix
@@ -45,6 +92,11 @@ func (h *Hasher) SetPrefix(prefix string) *Hasher {
 	return h
 }
 
+// Prefix of the hashes
+func (h *Hasher) Prefix() string {
+	return h.options.Prefix
+}
+
 // ClearPrefix explicitly
 func (h *Hasher) ClearPrefix() *Hasher {
 	h.options.Prefix = ""
@@ -66,7 +118,7 @@ func (h *Hasher) Encode(v ...interface{}) (string, error) {
 	for _, item := range v {
 		switch value := item.(type) {
 		case []int64:
-			h.numbers = value
+			h.numbers = append(h.numbers[:0], value...)
 		case []int:
 			for _, n := range value {
 				h.numbers = append(h.numbers, int64(n))
@@ -87,6 +139,33 @@ func (h *Hasher) Encode(v ...interface{}) (string, error) {
 	return h.encodeNumbers()
 }
 
+// AppendEncode appends the hash of numbers to dst and returns the extended buffer,
+// once the internal buffers have grown it does not allocate
+func (h *Hasher) AppendEncode(dst []byte, numbers ...int64) ([]byte, error) {
+	h.reset()
+
+	if err := h.hashNumbers(numbers); err != nil {
+		return dst, err
+	}
+
+	if err := h.checkLength(); err != nil {
+		return dst, err
+	}
+
+	dst = append(dst, h.options.Prefix...)
+	if h.bytes != nil {
+		dst = h.bytes.appendTo(dst)
+	} else {
+		dst = h.runes.appendTo(dst)
+	}
+
+	if h.tagger != nil {
+		dst = h.tagger.appendTag(dst, numbers)
+	}
+
+	return dst, nil
+}
+
 // EncodeHex - hexidecimal values
 func (h *Hasher) EncodeHex(hex string) (string, error) {
 	if isHex(hex) {
@@ -101,9 +180,12 @@ func (h *Hasher) EncodeHex(hex string) (string, error) {
 	return "", fmt.Errorf("unkown format of string")
 }
 
-// EncodeTime object
+// EncodeTime object as the count of TimePrecision units since Epoch
 func (h *Hasher) EncodeTime(t time.Time) (string, error) {
-	timestamp := t.UnixNano()
+	timestamp, err := h.options.timestamps().number(t)
+	if err != nil {
+		return "", err
+	}
 
 	return h.Encode(timestamp)
 }
@@ -113,125 +195,159 @@ func (h *Hasher) Decode(input string) *DecodedResult {
 	h.reset()
 
 	input = removePrefix(input, h.options.Prefix)
-	hashGroups := separate([]rune(input), h.options.guards)
-	i := 0
 
-	if len(hashGroups) == 2 || len(hashGroups) == 3 {
-		i = 1
+	var tag string
+	if h.tagger != nil {
+		var ok bool
+		if input, tag, ok = h.tagger.split(input); !ok {
+			return h.result(nil, ErrTagMismatch)
+		}
 	}
 
-	breakdown := hashGroups[i]
+	var verified bool
+	var err error
+	if h.bytes != nil {
+		h.numbers, verified, err = h.bytes.decode(input, h.numbers)
+	} else {
+		h.numbers, verified, err = h.runes.decode(input, h.numbers)
+	}
 
-	if len(breakdown) == 0 {
-		breakdown = hashGroups[0]
+	if err != nil {
+		return h.result(nil, err)
 	}
 
-	if len(breakdown) > 0 {
-		lottery := breakdown[0]
-		breakdown = breakdown[1:]
-		hashGroups = separate(breakdown, h.options.seps)
-		alphabet := h.options.alphabetCopy()
-		for _, rs := range hashGroups {
-			h.buf = h.buf[:1]
-			h.buf[0] = lottery
-			h.buf = append(h.buf, h.options.salt...)
-			h.buf = append(h.buf, alphabet...)
-			alphabet = shuffle(alphabet, h.buf[:len(alphabet)])
-			number, err := unhash(rs, alphabet)
-			if err != nil {
-				return NewDecodedResult(nil, err)
-			}
-			h.numbers = append(h.numbers, number)
+	// structural verification accepts exactly the same hashes as encoding
+	// the numbers once again, the latter gives a detailed error though
+	if !verified {
+		if err := h.checkDecode(input); err != nil {
+			return h.result(nil, err)
 		}
 	}
 
-	if err := h.checkDecode(input); err != nil {
-		return NewDecodedResult(nil, err)
+	// the tag is checked only for a valid hash, so it tells about tampering
+	if h.tagger != nil && !h.tagger.verify(tag, h.numbers) {
+		return h.result(nil, ErrTagMismatch)
 	}
 
-	return NewDecodedResult(h.numbers, nil)
+	return h.result(h.numbers, nil)
+}
+
+// result of decoding with the timestamps of the hasher
+func (h *Hasher) result(numbers []int64, err error) *DecodedResult {
+	result := NewDecodedResult(numbers, err)
+	result.timestamps = h.options.timestamps()
+
+	return result
 }
 
 func (h *Hasher) checkDecode(input string) error {
-	check, err := h.Encode(h.numbers)
+	h.resetHash()
+
+	err := h.hashNumbers(h.numbers)
+	if err == nil {
+		err = h.checkLength()
+	}
+
 	if err != nil {
 		return fmt.Errorf("error when trying to verify result: %v", err)
 	}
 
-	if removePrefix(check, h.options.Prefix) != input {
-		return fmt.Errorf("mismatch between encoded and decoded values: %s -> %s, obtained result %v", check, input, h.numbers)
+	if !h.hashEquals(input) {
+		return fmt.Errorf("mismatch between encoded and decoded values: %s -> %s, obtained result %v", h.getHashString(), input, h.numbers)
 	}
 
 	return nil
 }
 
+// MaxEncodableValue - the largest single number that fits into MaxLength
+func (h *Hasher) MaxEncodableValue() int64 {
+	maxLength := h.options.maxLength()
+	if maxLength == 0 || h.options.OrderPreserving || h.getMaxResultLengthFor([]int64{math.MaxInt64}) <= maxLength {
+		return math.MaxInt64
+	}
+
+	// a single number is hashed as a lottery character
+	// followed by the number in the base of the alphabet
+	base := int64(len(h.options.alphabet))
+	v := int64(1)
+	for i := 1; i < maxLength; i++ {
+		v *= base
+	}
+
+	return v - 1
+}
+
 func (h *Hasher) encodeNumbers() (string, error) {
-	if len(h.numbers) == 0 {
-		return "", fmt.Errorf("cannot encode an empty slice of numbers")
+	if err := h.hashNumbers(h.numbers); err != nil {
+		return "", err
 	}
 
-	for _, n := range h.numbers {
-		if n < 0 {
-			return "", fmt.Errorf("negative numbers like %d are not allowed", n)
-		}
+	if err := h.checkLength(); err != nil {
+		return "", err
 	}
 
-	alphabet := h.options.alphabetCopy()
-	numbersHashInt := createNumbersHashInt(h.numbers)
-	lottery := alphabet[numbersHashInt%int64(len(alphabet))]
-	salt := h.options.saltCopy()
+	return h.encoded(h.numbers), nil
+}
 
-	h.hash = append(h.hash, lottery)
+// encoded hash with prefix and tag
+func (h *Hasher) encoded(numbers []int64) string {
+	if h.tagger == nil {
+		return h.getHashString()
+	}
 
-	for i, n := range h.numbers {
-		h.buf = h.buf[:1]
-		h.buf[0] = lottery
-		h.buf = append(h.buf, salt...)
-		h.buf = append(h.buf, alphabet...)
-		alphabet = shuffle(alphabet, h.buf[:len(alphabet)])
+	h.tag = h.tagger.appendTag(h.tag[:0], numbers)
 
-		hashSlice := hash(n, alphabet)
-		h.hash = append(h.hash, hashSlice...)
+	return h.getHashString() + string(h.tag)
+}
 
-		if i < len(h.numbers)-1 {
-			n %= int64(hashSlice[0]) + int64(i)
-			h.hash = append(h.hash, h.options.seps[n%int64(len(h.options.seps))])
-		}
+// hashNumbers reusing the internal buffers
+func (h *Hasher) hashNumbers(numbers []int64) error {
+	if h.bytes != nil {
+		return h.bytes.hashNumbers(numbers)
 	}
 
-	h.extendHash(alphabet, numbersHashInt)
+	return h.runes.hashNumbers(numbers)
+}
 
-	return h.getHashString(), nil
+func (h *Hasher) checkLength() error {
+	if maxLength := h.options.maxLength(); maxLength > 0 && h.hashLen() > maxLength {
+		return &LengthError{Length: h.hashLen(), MaxLength: maxLength}
+	}
+
+	return nil
 }
 
-func (h *Hasher) extendHash(alphabet []rune, numbersHash int64) {
-	if len(h.hash) < h.options.Length {
-		i := (numbersHash + int64(h.hash[0])) % int64(len(h.options.guards))
-		h.hash = append([]rune{h.options.guards[i]}, h.hash...)
+// hashLen in characters
+func (h *Hasher) hashLen() int {
+	if h.bytes != nil {
+		return len(h.bytes.hash)
+	}
 
-		if len(h.hash) < h.options.Length {
-			i := (numbersHash + int64(h.hash[2])) % int64(len(h.options.guards))
-			h.hash = append(h.hash, h.options.guards[i])
-		}
+	return len(h.runes.hash)
+}
+
+func (h *Hasher) hashEquals(s string) bool {
+	if h.bytes != nil {
+		return h.bytes.equals(s)
 	}
 
-	middle := len(alphabet) / 2
-	for len(h.hash) < h.options.Length {
-		alphabet = shuffle(alphabet, alphabet)
-		h.hash = append(alphabet[middle:], append(h.hash, alphabet[:middle]...)...)
-		excess := len(h.hash) - h.options.Length
-		if excess > 0 {
-			h.hash = h.hash[excess/2 : excess/2+h.options.Length]
-		}
+	return h.runes.equals(s)
+}
+
+func (h *Hasher) hashString() string {
+	if h.bytes != nil {
+		return h.bytes.String()
 	}
+
+	return h.runes.String()
 }
 
 func (h Hasher) getHashString() string {
 	if h.options.Prefix != "" {
-		return prependWithPrefix(string(h.hash), h.options.Prefix)
+		return prependWithPrefix(h.hashString(), h.options.Prefix)
 	}
 
-	return string(h.hash)
+	return h.hashString()
 }
 
 func (h Hasher) getMaxResultLengthFor(slice []int64) int {
@@ -242,9 +358,16 @@ func (h Hasher) getMaxResultLengthFor(slice []int64) int {
 	return maxLength
 }
 
-// reset the hash
+// reset the hash, internal buffers are reused
 func (h *Hasher) reset() {
-	h.numbers = make([]int64, 0)
-	h.hash = make([]rune, 0, h.options.Length)
-	h.buf = make([]rune, 0, len(h.options.alphabet)+len(h.options.salt)+1)
+	h.numbers = h.numbers[:0]
+	h.resetHash()
+}
+
+func (h *Hasher) resetHash() {
+	if h.bytes != nil {
+		h.bytes.reset()
+	} else {
+		h.runes.reset()
+	}
 }
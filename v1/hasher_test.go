@@ -295,3 +295,96 @@ func Test_InvalidInput(t *testing.T) {
 		})
 	}
 }
+
+func Test_AppendEncode(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		input   []int64
+		options Options
+	}{
+		{[]int64{1}, DefaultOptions("test salt")},
+		{[]int64{45, 434, 1313, 99}, Options{Salt: "this is my salt", Length: 8}},
+		{[]int64{2, 24, 234567810}, Options{Salt: "test salt", Length: 20}},
+		{[]int64{1, 3, 7}, Options{Salt: "this is test salt", Length: 8, Alphabet: "98АБВГДЕжзиклмнпрсто1234", Prefix: "преф_"}},
+		{[]int64{1000000001, 1000000002, 1000000003}, Options{Salt: "my test salt", Length: 999}},
+	}
+
+	for _, tc := range tt {
+		tc := tc
+
+		t.Run(fmt.Sprintf("%v", tc.input), func(t *testing.T) {
+			h, err := New(tc.options)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			expected, err := h.Encode(tc.input)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			dst, err := h.AppendEncode([]byte("/items/"), tc.input...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			assert.Equal(t, "/items/"+expected, string(dst))
+		})
+	}
+}
+
+func Test_AppendEncodeErrorKeepsDst(t *testing.T) {
+	h, _ := New(DefaultOptions("test salt"))
+
+	dst, err := h.AppendEncode([]byte("abc"), 1, -1)
+	assert.Equal(t, "negative numbers like -1 are not allowed", err.Error())
+	assert.Equal(t, "abc", string(dst))
+}
+
+func Test_AppendEncodeDoesNotAllocate(t *testing.T) {
+	h, _ := New(DefaultOptions("test salt"))
+	dst := make([]byte, 0, 64)
+
+	allocs := testing.AllocsPerRun(100, func() {
+		dst, _ = h.AppendEncode(dst[:0], 1, 2, 3, 1000000)
+	})
+
+	assert.Equal(t, float64(0), allocs)
+}
+
+func Benchmark_Encode(b *testing.B) {
+	h, _ := New(DefaultOptions("test salt"))
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := h.Encode(int64(i)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func Benchmark_AppendEncode(b *testing.B) {
+	h, _ := New(DefaultOptions("test salt"))
+	dst := make([]byte, 0, 64)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		var err error
+		if dst, err = h.AppendEncode(dst[:0], int64(i)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func Benchmark_Decode(b *testing.B) {
+	h, _ := New(DefaultOptions("test salt"))
+	hash, _ := h.Encode(123456789)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if err := h.Decode(hash).Err(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
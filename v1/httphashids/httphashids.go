@@ -0,0 +1,238 @@
+// Package httphashids decodes hashes from path values and query parameters
+// of net/http requests into the request context.
+package httphashids
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+
+	hashids "github.com/denismitr/go-hashids/v1"
+)
+
+// Source of a named value of the request
+type Source func(r *http.Request, name string) string
+
+// PathValue of a pattern wildcard, the default source
+func PathValue(r *http.Request, name string) string {
+	return r.PathValue(name)
+}
+
+// Query parameter
+func Query(r *http.Request, name string) string {
+	return r.URL.Query().Get(name)
+}
+
+// ErrorHandler writes the response when a value cannot be decoded
+type ErrorHandler func(w http.ResponseWriter, r *http.Request, err *Error)
+
+// NotFound - the default error response, an invalid id is a missing resource
+func NotFound(w http.ResponseWriter, r *http.Request, err *Error) {
+	http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+}
+
+// Status responds with the status code and its text
+func Status(code int) ErrorHandler {
+	return func(w http.ResponseWriter, r *http.Request, err *Error) {
+		http.Error(w, http.StatusText(code), code)
+	}
+}
+
+// Error of a value of the request
+type Error struct {
+	Name  string
+	Value string
+	Err   error
+}
+
+// Error message
+func (e *Error) Error() string {
+	return fmt.Sprintf("invalid %s %q: %v", e.Name, e.Value, e.Err)
+}
+
+// Unwrap the underlying error
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Config of the middleware, either Hasher or Registry must be set
+type Config struct {
+	Hasher   *hashids.Hasher
+	Registry *Registry
+
+	// Kinds of the registry that are accepted, all when empty
+	Kinds []string
+
+	// Source of the values, PathValue when nil
+	Source Source
+
+	// OnError responds to invalid values, NotFound when nil
+	OnError ErrorHandler
+}
+
+// Value decoded from the request
+type Value struct {
+	Kind    string
+	Numbers []int64
+}
+
+type contextKey struct{}
+
+// Middleware decodes the named values and stores them in the request context,
+// the request is not passed on when any of them is invalid
+func Middleware(cfg Config, names ...string) func(http.Handler) http.Handler {
+	d := newDecoder(cfg)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			for _, name := range names {
+				var ok bool
+				if r, ok = d.decode(w, r, name); !ok {
+					return
+				}
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// Route decodes the named value with the registry of the config and
+// passes the request to the handler of its kind
+func Route(cfg Config, name string, handlers map[string]http.Handler) http.Handler {
+	if cfg.Registry == nil {
+		panic("httphashids: Route requires a Registry")
+	}
+
+	d := newDecoder(cfg)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r, ok := d.decode(w, r, name)
+		if !ok {
+			return
+		}
+
+		v, _ := Get(r, name)
+
+		handler, ok := handlers[v.Kind]
+		if !ok {
+			d.onError(w, r, &Error{Name: name, Value: d.source(r, name), Err: fmt.Errorf("no route for kind %s", v.Kind)})
+			return
+		}
+
+		handler.ServeHTTP(w, r)
+	})
+}
+
+// Get the decoded value
+func Get(r *http.Request, name string) (Value, bool) {
+	values, _ := r.Context().Value(contextKey{}).(map[string]Value)
+	v, ok := values[name]
+
+	return v, ok
+}
+
+// Numbers of the decoded value
+func Numbers(r *http.Request, name string) ([]int64, bool) {
+	v, ok := Get(r, name)
+
+	return v.Numbers, ok
+}
+
+// Int64 - the single number of the decoded value
+func Int64(r *http.Request, name string) (int64, bool) {
+	v, ok := Get(r, name)
+	if !ok || len(v.Numbers) != 1 {
+		return 0, false
+	}
+
+	return v.Numbers[0], true
+}
+
+// Kind of the decoded value, empty without a registry
+func Kind(r *http.Request, name string) (string, bool) {
+	v, ok := Get(r, name)
+
+	return v.Kind, ok
+}
+
+type decoder struct {
+	cfg     Config
+	pool    *sync.Pool
+	source  Source
+	onError ErrorHandler
+}
+
+func newDecoder(cfg Config) *decoder {
+	if (cfg.Hasher == nil) == (cfg.Registry == nil) {
+		panic("httphashids: either Hasher or Registry must be set")
+	}
+
+	d := &decoder{cfg: cfg, source: cfg.Source, onError: cfg.OnError}
+
+	if cfg.Hasher != nil {
+		d.pool = pool(cfg.Hasher)
+	}
+
+	if d.source == nil {
+		d.source = PathValue
+	}
+
+	if d.onError == nil {
+		d.onError = NotFound
+	}
+
+	return d
+}
+
+// decode the named value into a copy of the request, responds with an error
+// and returns false when the value is invalid
+func (d *decoder) decode(w http.ResponseWriter, r *http.Request, name string) (*http.Request, bool) {
+	value := d.source(r, name)
+
+	v, err := d.value(value)
+	if err != nil {
+		d.onError(w, r, &Error{Name: name, Value: value, Err: err})
+		return r, false
+	}
+
+	old, _ := r.Context().Value(contextKey{}).(map[string]Value)
+	values := make(map[string]Value, len(old)+1)
+	for k, v := range old {
+		values[k] = v
+	}
+
+	values[name] = v
+
+	return r.WithContext(context.WithValue(r.Context(), contextKey{}, values)), true
+}
+
+func (d *decoder) value(value string) (Value, error) {
+	if value == "" {
+		return Value{}, fmt.Errorf("value is missing")
+	}
+
+	if d.pool != nil {
+		numbers, err := decode(d.pool, value)
+
+		return Value{Numbers: numbers}, err
+	}
+
+	kind, numbers, err := d.cfg.Registry.Decode(value)
+	if err != nil {
+		return Value{}, err
+	}
+
+	if len(d.cfg.Kinds) == 0 {
+		return Value{Kind: kind, Numbers: numbers}, nil
+	}
+
+	for _, k := range d.cfg.Kinds {
+		if k == kind {
+			return Value{Kind: kind, Numbers: numbers}, nil
+		}
+	}
+
+	return Value{}, fmt.Errorf("kind %s is not accepted", kind)
+}
@@ -0,0 +1,190 @@
+package httphashids
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	hashids "github.com/denismitr/go-hashids/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+func newHasher(t *testing.T, options hashids.Options) *hashids.Hasher {
+	h, err := hashids.New(options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return h
+}
+
+func encode(t *testing.T, h *hashids.Hasher, numbers ...int64) string {
+	hash, err := h.Encode(numbers)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return hash
+}
+
+func serve(handler http.Handler, target string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, target, nil))
+
+	return w
+}
+
+func Test_Middleware(t *testing.T) {
+	t.Parallel()
+
+	h := newHasher(t, hashids.Options{Salt: "projects", Length: 8})
+
+	show := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, _ := Int64(r, "id")
+		numbers, _ := Numbers(r, "doc")
+		fmt.Fprintf(w, "%d %v", id, numbers)
+	})
+
+	mw := Middleware(Config{Hasher: h}, "id")
+	query := Middleware(Config{Hasher: h, Source: Query, OnError: Status(http.StatusBadRequest)}, "doc")
+
+	mux := http.NewServeMux()
+	mux.Handle("GET /projects/{id}", mw(query(show)))
+
+	tt := []struct {
+		name   string
+		target string
+		code   int
+		body   string
+	}{
+		{"valid", "/projects/" + encode(t, h, 5) + "?doc=" + encode(t, h, 1, 2), http.StatusOK, "5 [1 2]"},
+		{"invalid path value", "/projects/invalid?doc=" + encode(t, h, 1), http.StatusNotFound, "Not Found\n"},
+		{"invalid query", "/projects/" + encode(t, h, 5) + "?doc=invalid", http.StatusBadRequest, "Bad Request\n"},
+		{"missing query", "/projects/" + encode(t, h, 5), http.StatusBadRequest, "Bad Request\n"},
+	}
+
+	for _, tc := range tt {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			w := serve(mux, tc.target)
+
+			assert.Equal(t, tc.code, w.Code)
+			assert.Equal(t, tc.body, w.Body.String())
+		})
+	}
+}
+
+func Test_CustomErrorResponse(t *testing.T) {
+	t.Parallel()
+
+	h := newHasher(t, hashids.Options{Salt: "projects"})
+
+	var captured *Error
+	onError := func(w http.ResponseWriter, r *http.Request, err *Error) {
+		captured = err
+		w.WriteHeader(http.StatusUnprocessableEntity)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("GET /projects/{id}", Middleware(Config{Hasher: h, OnError: onError}, "id")(http.NotFoundHandler()))
+
+	w := serve(mux, "/projects/invalid")
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Equal(t, "id", captured.Name)
+	assert.Equal(t, "invalid", captured.Value)
+	assert.Contains(t, captured.Error(), `invalid id "invalid": `)
+	assert.NotNil(t, errors.Unwrap(captured))
+}
+
+func Test_RegistryRoute(t *testing.T) {
+	t.Parallel()
+
+	users := newHasher(t, hashids.Options{Salt: "users", Prefix: "usr_"})
+	admins := newHasher(t, hashids.Options{Salt: "admins", Prefix: "usr_adm_"})
+	orders := newHasher(t, hashids.Options{Salt: "orders", Prefix: "ord_"})
+
+	reg := NewRegistry()
+	assert.NoError(t, reg.Register("user", users))
+	assert.NoError(t, reg.Register("admin", admins))
+	assert.NoError(t, reg.Register("order", orders))
+	assert.EqualError(t, reg.Register("user", orders), "kind user is already registered")
+	assert.EqualError(t, reg.Register("customer", users), `prefix "usr_" of kind customer is already used by kind user`)
+
+	handler := func(name string) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			kind, _ := Kind(r, "id")
+			id, _ := Int64(r, "id")
+			fmt.Fprintf(w, "%s %s %d", name, kind, id)
+		})
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("GET /objects/{id}", Route(Config{Registry: reg}, "id", map[string]http.Handler{
+		"user":  handler("users"),
+		"admin": handler("admins"),
+		"order": handler("orders"),
+	}))
+	mux.Handle("GET /users/{id}", Middleware(Config{Registry: reg, Kinds: []string{"user", "admin"}}, "id")(handler("users")))
+
+	tt := []struct {
+		name   string
+		target string
+		code   int
+		body   string
+	}{
+		{"user", "/objects/" + encode(t, users, 1), http.StatusOK, "users user 1"},
+		{"longest prefix", "/objects/" + encode(t, admins, 2), http.StatusOK, "admins admin 2"},
+		{"order", "/objects/" + encode(t, orders, 3), http.StatusOK, "orders order 3"},
+		{"unknown prefix", "/objects/inv_abc", http.StatusNotFound, "Not Found\n"},
+		{"wrong salt", "/objects/usr_" + encode(t, newHasher(t, hashids.Options{Salt: "other"}), 1), http.StatusNotFound, "Not Found\n"},
+		{"accepted kind", "/users/" + encode(t, admins, 4), http.StatusOK, "users admin 4"},
+		{"not accepted kind", "/users/" + encode(t, orders, 4), http.StatusNotFound, "Not Found\n"},
+	}
+
+	for _, tc := range tt {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			w := serve(mux, tc.target)
+
+			assert.Equal(t, tc.code, w.Code)
+			assert.Equal(t, tc.body, w.Body.String())
+		})
+	}
+
+	kind, numbers, err := reg.Decode(encode(t, orders, 7, 8))
+	assert.NoError(t, err)
+	assert.Equal(t, "order", kind)
+	assert.Equal(t, []int64{7, 8}, numbers)
+}
+
+func Test_RouteWithoutHandler(t *testing.T) {
+	t.Parallel()
+
+	orders := newHasher(t, hashids.Options{Salt: "orders", Prefix: "ord_"})
+
+	reg := NewRegistry()
+	assert.NoError(t, reg.Register("order", orders))
+
+	var captured *Error
+	route := Route(Config{Registry: reg, OnError: func(w http.ResponseWriter, r *http.Request, err *Error) {
+		captured = err
+		NotFound(w, r, err)
+	}}, "id", map[string]http.Handler{})
+
+	mux := http.NewServeMux()
+	mux.Handle("GET /objects/{id}", route)
+
+	w := serve(mux, "/objects/"+encode(t, orders, 1))
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.EqualError(t, captured.Err, "no route for kind order")
+}
@@ -0,0 +1,79 @@
+package httphashids
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	hashids "github.com/denismitr/go-hashids/v1"
+)
+
+// Registry of hashers of several entity kinds, a hash is decoded
+// by the hasher whose prefix it starts with
+type Registry struct {
+	entries []*entry
+}
+
+type entry struct {
+	kind   string
+	prefix string
+	pool   *sync.Pool
+}
+
+// NewRegistry without kinds
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// Register a hasher of a kind, prefixes of kinds must differ,
+// the hasher is copied, so changing it afterwards has no effect
+func (reg *Registry) Register(kind string, h *hashids.Hasher) error {
+	for _, e := range reg.entries {
+		if e.kind == kind {
+			return fmt.Errorf("kind %s is already registered", kind)
+		}
+
+		if e.prefix == h.Prefix() {
+			return fmt.Errorf("prefix %q of kind %s is already used by kind %s", h.Prefix(), kind, e.kind)
+		}
+	}
+
+	reg.entries = append(reg.entries, &entry{kind: kind, prefix: h.Prefix(), pool: pool(h)})
+
+	// the longest prefix wins, e.g. usr_adm_ over usr_
+	sort.SliceStable(reg.entries, func(i, j int) bool {
+		return len(reg.entries[i].prefix) > len(reg.entries[j].prefix)
+	})
+
+	return nil
+}
+
+// Decode the hash with the hasher of its prefix, safe for concurrent use
+func (reg *Registry) Decode(hash string) (string, []int64, error) {
+	for _, e := range reg.entries {
+		if !strings.HasPrefix(hash, e.prefix) {
+			continue
+		}
+
+		numbers, err := decode(e.pool, hash)
+
+		return e.kind, numbers, err
+	}
+
+	return "", nil, fmt.Errorf("no kind is registered for the prefix of %s", hash)
+}
+
+// pool of copies of the hasher, a hasher is not safe for concurrent use
+func pool(h *hashids.Hasher) *sync.Pool {
+	h = h.Clone()
+
+	return &sync.Pool{New: func() interface{} { return h.Clone() }}
+}
+
+func decode(p *sync.Pool, hash string) ([]int64, error) {
+	h := p.Get().(*hashids.Hasher)
+	defer p.Put(h)
+
+	return h.Decode(hash).Unwrap()
+}
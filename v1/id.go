@@ -0,0 +1,183 @@
+package hashids
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// ID - integer key inside the program and a hash everywhere outside of it,
+// K is a kind of IDs that provides their codec, e.g.
+//
+//	var userIDs = hashids.NewIDCodec(h)
+//	type user struct{}
+//	func (user) IDCodec() *hashids.IDCodec { return userIDs }
+//	type User struct { ID hashids.ID[user] `json:"id"` }
+type ID[K IDKind] int64
+
+// IDKind - kind of IDs, the zero value of the type returns the codec of its IDs
+type IDKind interface {
+	IDCodec() *IDCodec
+}
+
+// IDCodec of IDs of a kind, every goroutine gets a copy of the hasher
+type IDCodec struct {
+	pool sync.Pool
+}
+
+// NewIDCodec with a copy of the hasher,
+// so changing it afterwards has no effect on the IDs
+func NewIDCodec(h *Hasher) *IDCodec {
+	h = h.Clone()
+
+	return &IDCodec{pool: sync.Pool{New: func() interface{} { return h.Clone() }}}
+}
+
+// errNoCodec - IDCodec of a kind is nil
+var errNoCodec = errors.New("the kind has no IDCodec")
+
+// Encode a single number
+func (c *IDCodec) Encode(n int64) (string, error) {
+	if c == nil {
+		return "", errNoCodec
+	}
+
+	h := c.pool.Get().(*Hasher)
+	defer c.pool.Put(h)
+
+	return h.Encode(n)
+}
+
+// Decode a hash of a single number
+func (c *IDCodec) Decode(hash string) (int64, error) {
+	if c == nil {
+		return 0, errNoCodec
+	}
+
+	h := c.pool.Get().(*Hasher)
+	defer c.pool.Put(h)
+
+	numbers, err := h.Decode(hash).Unwrap()
+	if err != nil {
+		return 0, err
+	}
+
+	if len(numbers) != 1 {
+		return 0, fmt.Errorf("expected a single number, got %d", len(numbers))
+	}
+
+	return numbers[0], nil
+}
+
+// IDError - ID could not be encoded or decoded
+type IDError struct {
+	Kind  string
+	Input string
+	Err   error
+}
+
+// Error message
+func (e *IDError) Error() string {
+	if e.Input == "" {
+		return fmt.Sprintf("invalid %s id: %v", e.Kind, e.Err)
+	}
+
+	return fmt.Sprintf("invalid %s id %q: %v", e.Kind, e.Input, e.Err)
+}
+
+// Unwrap the underlying error
+func (e *IDError) Unwrap() error {
+	return e.Err
+}
+
+// ParseID of kind K from a hash
+func ParseID[K IDKind](hash string) (ID[K], error) {
+	var id ID[K]
+	err := id.UnmarshalText([]byte(hash))
+
+	return id, err
+}
+
+// Int64 key
+func (id ID[K]) Int64() int64 {
+	return int64(id)
+}
+
+// Hash of the ID
+func (id ID[K]) Hash() (string, error) {
+	var kind K
+
+	hash, err := kind.IDCodec().Encode(int64(id))
+	if err != nil {
+		return "", &IDError{Kind: kindName[K](), Err: err}
+	}
+
+	return hash, nil
+}
+
+// String - the hash, or an empty string when it cannot be encoded
+func (id ID[K]) String() string {
+	hash, _ := id.Hash()
+
+	return hash
+}
+
+// MarshalText implements encoding.TextMarshaler
+func (id ID[K]) MarshalText() ([]byte, error) {
+	hash, err := id.Hash()
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(hash), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler
+func (id *ID[K]) UnmarshalText(text []byte) error {
+	var kind K
+
+	n, err := kind.IDCodec().Decode(string(text))
+	if err != nil {
+		return &IDError{Kind: kindName[K](), Input: string(text), Err: err}
+	}
+
+	*id = ID[K](n)
+
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler
+func (id ID[K]) MarshalJSON() ([]byte, error) {
+	hash, err := id.Hash()
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(hash)
+}
+
+// UnmarshalJSON implements json.Unmarshaler, null leaves the ID untouched
+func (id *ID[K]) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+
+	var hash string
+	if err := json.Unmarshal(data, &hash); err != nil {
+		return &IDError{Kind: kindName[K](), Input: string(data), Err: fmt.Errorf("id must be a JSON string")}
+	}
+
+	return id.UnmarshalText([]byte(hash))
+}
+
+// kindName of K for errors, unnamed types such as pointers have no name
+func kindName[K IDKind]() string {
+	t := reflect.TypeOf((*K)(nil)).Elem()
+	if t.Name() != "" {
+		return t.Name()
+	}
+
+	return t.String()
+}
@@ -0,0 +1,152 @@
+package hashids
+
+import (
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	userIDs  = newTestIDCodec(Options{Salt: "users", Length: 8, Prefix: "usr_"})
+	orderIDs = newTestIDCodec(Options{Salt: "orders", Length: 6})
+)
+
+func newTestIDCodec(options Options) *IDCodec {
+	h, _ := New(options)
+
+	return NewIDCodec(h)
+}
+
+type userKind struct{}
+
+func (userKind) IDCodec() *IDCodec { return userIDs }
+
+type orderKind struct{}
+
+func (orderKind) IDCodec() *IDCodec { return orderIDs }
+
+type unboundKind struct{}
+
+func (unboundKind) IDCodec() *IDCodec { return nil }
+
+type pointerKind struct{}
+
+func (*pointerKind) IDCodec() *IDCodec { return nil }
+
+type order struct {
+	ID     ID[orderKind]  `json:"id"`
+	UserID ID[userKind]   `json:"user_id"`
+	Parent *ID[orderKind] `json:"parent,omitempty"`
+}
+
+func Test_IDMarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	users, _ := New(Options{Salt: "users", Length: 8, Prefix: "usr_"})
+	orders, _ := New(Options{Salt: "orders", Length: 6})
+
+	userHash, _ := users.Encode(42)
+	orderHash, _ := orders.Encode(7)
+
+	data, err := json.Marshal(order{ID: 7, UserID: 42})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, `{"id":"`+orderHash+`","user_id":"`+userHash+`"}`, string(data))
+
+	var decoded order
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, order{ID: 7, UserID: 42}, decoded)
+	assert.Equal(t, int64(42), decoded.UserID.Int64())
+	assert.Equal(t, userHash, decoded.UserID.String())
+
+	id, err := ParseID[userKind](userHash)
+	assert.NoError(t, err)
+	assert.Equal(t, ID[userKind](42), id)
+}
+
+func Test_IDErrors(t *testing.T) {
+	t.Parallel()
+
+	orders, _ := New(Options{Salt: "orders", Length: 6})
+	pair, _ := orders.Encode(1, 2)
+
+	tt := []struct {
+		name string
+		data string
+		err  string
+	}{
+		{"not a hash", `{"id": "!!!"}`, `invalid orderKind id "!!!": `},
+		{"wrong hasher", `{"user_id": "` + pair + `"}`, `invalid userKind id "` + pair + `": `},
+		{"several numbers", `{"id": "` + pair + `"}`, `invalid orderKind id "` + pair + `": expected a single number, got 2`},
+		{"not a string", `{"id": 12}`, `invalid orderKind id "12": id must be a JSON string`},
+	}
+
+	for _, tc := range tt {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			var o order
+			err := json.Unmarshal([]byte(tc.data), &o)
+
+			var idErr *IDError
+			assert.True(t, errors.As(err, &idErr))
+			assert.Contains(t, err.Error(), tc.err)
+		})
+	}
+
+	_, err := ID[unboundKind](1).MarshalText()
+	assert.EqualError(t, err, "invalid unboundKind id: the kind has no IDCodec")
+	assert.Equal(t, "", ID[unboundKind](1).String())
+
+	_, err = ParseID[*pointerKind]("abc")
+	assert.EqualError(t, err, `invalid *hashids.pointerKind id "abc": the kind has no IDCodec`)
+
+	_, err = ID[userKind](-1).Hash()
+	assert.EqualError(t, err, "invalid userKind id: negative numbers like -1 are not allowed")
+}
+
+func Test_IDNullLeavesValue(t *testing.T) {
+	t.Parallel()
+
+	parent := ID[orderKind](5)
+	o := order{Parent: &parent}
+
+	assert.NoError(t, json.Unmarshal([]byte(`{"parent": null}`), &o))
+	assert.Nil(t, o.Parent)
+
+	id := ID[orderKind](5)
+	assert.NoError(t, id.UnmarshalJSON([]byte("null")))
+	assert.Equal(t, ID[orderKind](5), id)
+}
+
+func Test_IDIsSafeForConcurrentUse(t *testing.T) {
+	t.Parallel()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			for n := int64(0); n < 200; n++ {
+				id := ID[userKind](n * int64(i+1))
+				text, err := id.MarshalText()
+				assert.NoError(t, err)
+
+				var decoded ID[userKind]
+				assert.NoError(t, decoded.UnmarshalText(text))
+				assert.Equal(t, id, decoded)
+			}
+		}(i)
+	}
+
+	wg.Wait()
+}
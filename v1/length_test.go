@@ -0,0 +1,173 @@
+package hashids
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ExactLength(t *testing.T) {
+	t.Parallel()
+
+	options := DefaultOptions("test salt")
+	options.Length = 12
+	options.ExactLength = true
+
+	h, err := New(options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, n := range []int64{0, 1, 1000, 123456789, h.MaxEncodableValue()} {
+		hash, err := h.Encode(n)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, 12, len(hash))
+
+		result, err := h.Decode(hash).FirstInt64()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, n, result)
+	}
+
+	_, err = h.Encode(h.MaxEncodableValue() + 1)
+
+	var lengthErr *LengthError
+	if !errors.As(err, &lengthErr) {
+		t.Fatalf("expected LengthError, got %v", err)
+	}
+
+	assert.Equal(t, 13, lengthErr.Length)
+	assert.Equal(t, 12, lengthErr.MaxLength)
+}
+
+func Test_MaxLength(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		alphabet  string
+		length    int
+		maxLength int
+		input     []int64
+		fits      bool
+	}{
+		{DefaultAlphabet, 0, 4, []int64{1}, true},
+		{DefaultAlphabet, 0, 4, []int64{1, 2}, true},
+		{DefaultAlphabet, 0, 4, []int64{1, 2, 3}, false},
+		{DefaultAlphabet, 8, 10, []int64{1, 2, 3}, true},
+		{DefaultAlphabet, 8, 10, []int64{math.MaxInt64}, false},
+		{NumericAlphabet, 0, 8, []int64{999}, true},
+		{NumericAlphabet, 0, 8, []int64{100500100}, false},
+	}
+
+	for _, tc := range tt {
+		tc := tc
+
+		t.Run(fmt.Sprintf("%v max length %d", tc.input, tc.maxLength), func(t *testing.T) {
+			options := Options{
+				Alphabet:  tc.alphabet,
+				Length:    tc.length,
+				MaxLength: tc.maxLength,
+				Salt:      "test salt",
+				Prefix:    "id_",
+			}
+
+			h, err := New(options)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			hash, err := h.Encode(tc.input)
+			if !tc.fits {
+				var lengthErr *LengthError
+				assert.True(t, errors.As(err, &lengthErr))
+				assert.Equal(t, "", hash)
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			assert.True(t, len(hash)-len("id_") <= tc.maxLength)
+			assert.True(t, len(hash)-len("id_") >= tc.length)
+		})
+	}
+}
+
+func Test_MaxEncodableValue(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		alphabet  string
+		maxLength int
+		exact     bool
+		expected  int64
+	}{
+		{NumericAlphabet, 0, false, math.MaxInt64},
+		{NumericAlphabet, 2, false, 5},
+		{NumericAlphabet, 4, false, 215},
+		{NumericAlphabet, 100, false, math.MaxInt64},
+		{DefaultAlphabet, 30, false, math.MaxInt64},
+		{NumericAlphabet, 2, true, 5},
+		{NumericAlphabet, 4, true, 215},
+		{DefaultAlphabet, 30, true, math.MaxInt64},
+	}
+
+	for _, tc := range tt {
+		tc := tc
+
+		t.Run(fmt.Sprintf("%s %d %v", tc.alphabet, tc.maxLength, tc.exact), func(t *testing.T) {
+			options := Options{Alphabet: tc.alphabet, MaxLength: tc.maxLength, Salt: "test salt"}
+			if tc.exact {
+				options = Options{Alphabet: tc.alphabet, Length: tc.maxLength, ExactLength: true, Salt: "test salt"}
+			}
+
+			h, err := New(options)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			assert.Equal(t, tc.expected, h.MaxEncodableValue())
+
+			hash, err := h.Encode(h.MaxEncodableValue())
+			assert.Nil(t, err)
+
+			if tc.exact {
+				assert.Equal(t, tc.maxLength, len(hash))
+			}
+		})
+	}
+}
+
+func Test_InvalidLengthOptions(t *testing.T) {
+	tt := []struct {
+		options Options
+		err     string
+	}{
+		{Options{Length: 1, ExactLength: true}, "exact length requires Length of at least 2"},
+		{Options{ExactLength: true}, "exact length requires Length of at least 2"},
+		{Options{MaxLength: 1}, "MaxLength must be at least 2"},
+		{Options{Length: 16, MaxLength: 12}, "MaxLength 12 cannot be smaller than Length 16"},
+	}
+
+	for _, tc := range tt {
+		tc := tc
+
+		t.Run(tc.err, func(t *testing.T) {
+			_, err := New(tc.options)
+			if err == nil {
+				t.Fatal("err should not be nil")
+			}
+
+			assert.Equal(t, tc.err, err.Error())
+		})
+	}
+}
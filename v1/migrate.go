@@ -0,0 +1,185 @@
+package hashids
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Migrator re-encodes hashes from one configuration to another,
+// e.g. when salt or alphabet is changed
+type Migrator struct {
+	from *Hasher
+	to   *Hasher
+}
+
+// MigrationReport of a migration run, failed values are not in the mapping
+type MigrationReport struct {
+	Total    int
+	Migrated int
+	Failed   []*LineError
+}
+
+// NewMigrator from old options to new ones
+func NewMigrator(from, to Options) (*Migrator, error) {
+	fromHasher, err := New(from)
+	if err != nil {
+		return nil, fmt.Errorf("invalid options to migrate from: %v", err)
+	}
+
+	toHasher, err := New(to)
+	if err != nil {
+		return nil, fmt.Errorf("invalid options to migrate to: %v", err)
+	}
+
+	return &Migrator{from: fromHasher, to: toHasher}, nil
+}
+
+// Migrate a single hash
+func (m *Migrator) Migrate(hash string) (string, error) {
+	numbers, err := m.from.Decode(hash).Unwrap()
+	if err != nil {
+		return "", err
+	}
+
+	return m.to.Encode(numbers)
+}
+
+// MigrateValues reads a hash per line and writes old,new mapping as CSV to w,
+// lines are read with a LineReader and blank ones are skipped
+func (m *Migrator) MigrateValues(r io.Reader, w io.Writer) (*MigrationReport, error) {
+	mw, report := m.mapping(w)
+	lines := NewLineReader(r)
+
+	for lines.Scan() {
+		if lines.Blank() {
+			continue
+		}
+
+		m.migrate(mw, report, lines.Line(), lines.Text())
+	}
+
+	if err := lines.Err(); err != nil {
+		return report, err
+	}
+
+	return report, m.flush(mw)
+}
+
+// MigrateCSV migrates hashes from the column with a given header name
+// and writes old,new mapping as CSV to w
+func (m *Migrator) MigrateCSV(r io.Reader, w io.Writer, column string) (*MigrationReport, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("unable to read CSV header: %v", err)
+	}
+
+	index := -1
+	for i, name := range header {
+		if strings.TrimSpace(name) == column {
+			index = i
+			break
+		}
+	}
+
+	if index == -1 {
+		return nil, fmt.Errorf("column %s not found in CSV header", column)
+	}
+
+	mw, report := m.mapping(w)
+
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			if _, ok := err.(*csv.ParseError); !ok {
+				return report, err
+			}
+
+			report.Total++
+			report.Failed = append(report.Failed, &LineError{Line: err.(*csv.ParseError).Line, Err: err})
+			continue
+		}
+
+		line, _ := cr.FieldPos(0)
+		if index >= len(record) {
+			report.Total++
+			report.Failed = append(report.Failed, &LineError{Line: line, Err: fmt.Errorf("column %s is missing", column)})
+			continue
+		}
+
+		m.migrate(mw, report, line, strings.TrimSpace(record[index]))
+	}
+
+	return report, m.flush(mw)
+}
+
+// MigrateJSONL migrates hashes from a top level string field of every line
+// and writes old,new mapping as CSV to w, lines are read with a LineReader
+// and blank ones are skipped
+func (m *Migrator) MigrateJSONL(r io.Reader, w io.Writer, field string) (*MigrationReport, error) {
+	mw, report := m.mapping(w)
+	lines := NewLineReader(r)
+
+	for lines.Scan() {
+		if lines.Blank() {
+			continue
+		}
+
+		var object map[string]interface{}
+		if err := json.Unmarshal(lines.Bytes(), &object); err != nil {
+			report.Total++
+			report.Failed = append(report.Failed, lines.Error(err))
+			continue
+		}
+
+		value, ok := object[field].(string)
+		if !ok {
+			report.Total++
+			report.Failed = append(report.Failed, lines.Error(fmt.Errorf("field %s is not a string", field)))
+			continue
+		}
+
+		m.migrate(mw, report, lines.Line(), value)
+	}
+
+	if err := lines.Err(); err != nil {
+		return report, err
+	}
+
+	return report, m.flush(mw)
+}
+
+func (m *Migrator) mapping(w io.Writer) (*csv.Writer, *MigrationReport) {
+	mw := csv.NewWriter(w)
+	mw.Write([]string{"old", "new"})
+
+	return mw, &MigrationReport{}
+}
+
+func (m *Migrator) migrate(mw *csv.Writer, report *MigrationReport, line int, value string) {
+	report.Total++
+
+	hash, err := m.Migrate(value)
+	if err != nil {
+		report.Failed = append(report.Failed, &LineError{Line: line, Input: value, Err: err})
+		return
+	}
+
+	report.Migrated++
+	mw.Write([]string{value, hash})
+}
+
+func (m *Migrator) flush(mw *csv.Writer) error {
+	mw.Flush()
+
+	return mw.Error()
+}
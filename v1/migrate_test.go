@@ -0,0 +1,103 @@
+package hashids
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestMigrator(t *testing.T) (*Migrator, *Hasher, *Hasher) {
+	from := Options{Salt: "old salt", Length: 8}
+	to := Options{Salt: "new salt", Length: 10, Alphabet: LowercaseAlphabetWithDigits, Prefix: "id_"}
+
+	m, err := NewMigrator(from, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	old, _ := New(from)
+	current, _ := New(to)
+
+	return m, old, current
+}
+
+func Test_Migrate(t *testing.T) {
+	t.Parallel()
+
+	m, old, current := newTestMigrator(t)
+
+	hash, _ := old.Encode(1, 2, 3)
+	migrated, err := m.Migrate(hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected, _ := current.Encode(1, 2, 3)
+	assert.Equal(t, expected, migrated)
+
+	_, err = m.Migrate(expected)
+	assert.Error(t, err)
+
+	_, err = NewMigrator(Options{Alphabet: "abc"}, Options{})
+	assert.Equal(t, "invalid options to migrate from: Alphabet length must be at least 10", err.Error())
+}
+
+func Test_MigrateValuesCSVAndJSONL(t *testing.T) {
+	t.Parallel()
+
+	m, old, current := newTestMigrator(t)
+
+	a, _ := old.Encode(10)
+	b, _ := old.Encode(20, 30)
+	newA, _ := current.Encode(10)
+	newB, _ := current.Encode(20, 30)
+
+	expected := "old,new\n" + a + "," + newA + "\n" + b + "," + newB + "\n"
+
+	tt := []struct {
+		name    string
+		migrate func(w *bytes.Buffer) (*MigrationReport, error)
+		line    int
+	}{
+		{"values", func(w *bytes.Buffer) (*MigrationReport, error) {
+			return m.MigrateValues(strings.NewReader(a+"\n\ninvalid\n"+b+"\n"), w)
+		}, 3},
+		{"csv", func(w *bytes.Buffer) (*MigrationReport, error) {
+			in := "name,id\nfirst," + a + "\nsecond,invalid\nthird," + b + "\n"
+			return m.MigrateCSV(strings.NewReader(in), w, "id")
+		}, 3},
+		{"jsonl", func(w *bytes.Buffer) (*MigrationReport, error) {
+			in := `{"id": "` + a + `"}` + "\n  \n" + `{"id": "invalid", "n": 1}` + "\n" + `{"id": "` + b + `"}` + "\n"
+			return m.MigrateJSONL(strings.NewReader(in), w, "id")
+		}, 3},
+	}
+
+	for _, tc := range tt {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			var w bytes.Buffer
+
+			report, err := tc.migrate(&w)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			assert.Equal(t, expected, w.String())
+			assert.Equal(t, 3, report.Total)
+			assert.Equal(t, 2, report.Migrated)
+			assert.Equal(t, 1, len(report.Failed))
+			assert.Equal(t, tc.line, report.Failed[0].Line)
+			assert.Equal(t, "invalid", report.Failed[0].Input)
+		})
+	}
+}
+
+func Test_MigrateCSVMissingColumn(t *testing.T) {
+	m, _, _ := newTestMigrator(t)
+
+	_, err := m.MigrateCSV(strings.NewReader("name,hash\n"), &bytes.Buffer{}, "id")
+	assert.Equal(t, "column id not found in CSV header", err.Error())
+}
@@ -1,8 +1,11 @@
 package hashids
 
 import (
+	"crypto/aes"
 	"fmt"
 	"math"
+	"time"
+	"unicode/utf8"
 )
 
 const (
@@ -12,11 +15,21 @@ const (
 	// LowercaseAlphabetWithDigits all latin lowercase characters and digits
 	LowercaseAlphabetWithDigits = "abcdefghijklmnopqrstuvwxyz1234567890"
 
+	// NumericAlphabet - digits only, for order numbers, PIN-like references and such
+	NumericAlphabet = "0123456789"
+
 	// DefaultLength of the hash which is basically a minimal length of the hash
 	// Length will grow automatically as required
 	DefaultLength = 16
 	// MinAlphabetLength - custome alphabet cannot be smaller than this value
-	MinAlphabetLength = 16
+	MinAlphabetLength = 10
+
+	// minHashAlphabetLength - at least two characters must stay in the alphabet
+	// after seps and guards are taken out of it, otherwise numbers cannot be hashed
+	minHashAlphabetLength = 2
+
+	// minHashLength - shortest possible hash is a lottery character and a single digit
+	minHashLength = 2
 
 	sepDiv      = 3.5
 	guardDiv    = 12.0
@@ -30,10 +43,49 @@ type Options struct {
 	Salt     string
 	Prefix   string
 
+	// MaxLength of the hash without prefix, 0 means no limit
+	MaxLength int
+	// ExactLength makes Length both minimal and maximal length of the hash
+	ExactLength bool
+
+	// OrderPreserving makes hashes of the same hasher sort like their numbers,
+	// at the cost of obfuscation, see README
+	OrderPreserving bool
+
+	// Key of format preserving encryption of numbers before hashing,
+	// 16, 24 or 32 bytes for AES-128, AES-192 or AES-256
+	Key []byte
+
+	// ScrambleKey of a fast bijective scramble of numbers before hashing,
+	// so that hashes of neighbouring numbers look unrelated, 0 turns it off
+	ScrambleKey uint64
+
+	// Secret of HMAC tags appended to hashes, Decode rejects hashes
+	// whose tag does not match with ErrTagMismatch
+	Secret []byte
+	// TagLength in characters, DefaultTagLength when 0, at most 16,
+	// Length and MaxLength do not count the tag
+	TagLength int
+
+	// ExpiryPrecision of EncodeWithExpiry, DefaultExpiryPrecision when 0,
+	// coarser precision makes shorter hashes
+	ExpiryPrecision time.Duration
+
+	// TimePrecision of EncodeTime and AsTime, time.Nanosecond when 0,
+	// e.g. time.Second makes much shorter hashes
+	TimePrecision time.Duration
+	// Epoch timestamps count from, Unix epoch when zero,
+	// e.g. the launch date of the service makes numbers smaller
+	Epoch time.Time
+	// Location of times returned by AsTime, UTC when nil
+	Location *time.Location
+
 	alphabet []rune
 	salt     []rune
 	seps     []rune
 	guards   []rune
+	// digits of every position of a number, only in order preserving mode
+	digits [][]rune
 }
 
 // DefaultOptions for the obfuscator
@@ -52,9 +104,53 @@ func (o *Options) initialize() error {
 		return err
 	}
 
+	if err := o.validateLength(); err != nil {
+		return err
+	}
+
 	o.salt = []rune(o.Salt)
 	o.alphabet = alphabet
 
+	if o.Key != nil {
+		if o.OrderPreserving {
+			return fmt.Errorf("Key cannot be used in order preserving mode")
+		}
+
+		if _, err := aes.NewCipher(o.Key); err != nil {
+			return fmt.Errorf("invalid Key: %v", err)
+		}
+
+		if o.ScrambleKey != 0 {
+			return fmt.Errorf("Key and ScrambleKey cannot be used together")
+		}
+	}
+
+	if err := o.validateTag(); err != nil {
+		return err
+	}
+
+	if o.ExpiryPrecision < 0 {
+		return fmt.Errorf("ExpiryPrecision cannot be negative")
+	}
+
+	if o.ExpiryPrecision == 0 {
+		o.ExpiryPrecision = DefaultExpiryPrecision
+	}
+
+	if o.TimePrecision < 0 {
+		return fmt.Errorf("TimePrecision cannot be negative")
+	}
+
+	if o.ScrambleKey != 0 && o.OrderPreserving {
+		return fmt.Errorf("ScrambleKey cannot be used in order preserving mode")
+	}
+
+	if o.OrderPreserving {
+		if err := o.initializeDigits(); err != nil {
+			return err
+		}
+	}
+
 	o.calculateSeps()
 	o.createGuards()
 
@@ -91,6 +187,72 @@ func (o Options) validateAlphabet() ([]rune, error) {
 	return alphabetRunes, nil
 }
 
+func (o Options) validateLength() error {
+	if o.ExactLength && o.Length < minHashLength {
+		return fmt.Errorf("exact length requires Length of at least %d", minHashLength)
+	}
+
+	if o.MaxLength == 0 {
+		return nil
+	}
+
+	if o.MaxLength < minHashLength {
+		return fmt.Errorf("MaxLength must be at least %d", minHashLength)
+	}
+
+	if o.MaxLength < o.Length {
+		return fmt.Errorf("MaxLength %d cannot be smaller than Length %d", o.MaxLength, o.Length)
+	}
+
+	return nil
+}
+
+func (o *Options) validateTag() error {
+	if o.Secret == nil {
+		if o.TagLength != 0 {
+			return fmt.Errorf("TagLength requires Secret")
+		}
+
+		return nil
+	}
+
+	if len(o.Secret) == 0 {
+		return fmt.Errorf("Secret cannot be empty")
+	}
+
+	if o.TagLength == 0 {
+		o.TagLength = DefaultTagLength
+	}
+
+	if o.TagLength < 1 || o.TagLength > maxTagLength {
+		return fmt.Errorf("TagLength must be from 1 to %d", maxTagLength)
+	}
+
+	return nil
+}
+
+// maxLength of the hash, 0 means no limit
+func (o Options) maxLength() int {
+	if o.ExactLength {
+		return o.Length
+	}
+
+	return o.MaxLength
+}
+
+// isASCII - whether alphabet and salt contain only ASCII characters
+func (o Options) isASCII() bool {
+	for _, set := range [][]rune{o.alphabet, o.salt, o.seps, o.guards} {
+		for _, r := range set {
+			if r >= utf8.RuneSelf {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
 func (o Options) hasPrefix() bool {
 	return o.Prefix != ""
 }
@@ -130,6 +292,14 @@ func (o *Options) calculateSeps() {
 		}
 	}
 
+	// small alphabet may consist mostly of default seps,
+	// give some of them back so there is still something to hash with
+	if len(o.alphabet) < minHashAlphabetLength {
+		diff := minHashAlphabetLength - len(o.alphabet)
+		o.alphabet = append(o.alphabet, o.seps[len(o.seps)-diff:]...)
+		o.seps = o.seps[:len(o.seps)-diff]
+	}
+
 	o.seps = shuffle(o.seps, o.saltCopy())
 
 	if len(o.seps) == 0 || float64(len(o.alphabet))/float64(len(o.seps)) > sepDiv {
@@ -0,0 +1,149 @@
+package hashids
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math"
+	"math/bits"
+	"sort"
+)
+
+// ordered - order preserving encoding, every number is shifted by an offset
+// derived from the salt and written with the same count of digits, every
+// position has its own digits selected from the alphabet by the salt and
+// sorted by code points, so comparing hashes compares numbers
+type ordered[T symbol] struct {
+	digits [][]T
+	base   uint64
+	offset uint64
+}
+
+func newOrdered[T symbol](o Options) *ordered[T] {
+	od := &ordered[T]{
+		digits: make([][]T, len(o.digits)),
+		base:   uint64(len(o.digits[0])),
+		offset: o.orderedOffset(),
+	}
+
+	for i, digits := range o.digits {
+		od.digits[i] = toSymbols[T](digits)
+	}
+
+	return od
+}
+
+// appendHash of the numbers, which are known to be non negative
+func (od *ordered[T]) appendHash(hash []T, numbers []int64) []T {
+	for _, n := range numbers {
+		start := len(hash)
+		for _, digits := range od.digits {
+			hash = append(hash, digits[0])
+		}
+
+		v := uint64(n) + od.offset
+		for i := len(od.digits) - 1; v > 0; i-- {
+			hash[start+i] = od.digits[i][v%od.base]
+			v /= od.base
+		}
+	}
+
+	return hash
+}
+
+// decode input without prefix and append the numbers, every valid input
+// is exactly the hash of its numbers, so no other verification is needed
+func (od *ordered[T]) decode(input []T, numbers []int64) ([]int64, error) {
+	width := len(od.digits)
+	if len(input) == 0 || len(input)%width != 0 {
+		return numbers, fmt.Errorf("hash length %d must be a multiple of %d in order preserving mode", len(input), width)
+	}
+
+	for ; len(input) > 0; input = input[width:] {
+		var v uint64
+		for i, s := range input[:width] {
+			d := od.position(i, s)
+			if d < 0 {
+				return numbers, fmt.Errorf("alphabet that was used for hashing was different")
+			}
+
+			hi, lo := bits.Mul64(v, od.base)
+			lo, carry := bits.Add64(lo, uint64(d), 0)
+			if hi != 0 || carry != 0 {
+				return numbers, fmt.Errorf("number is out of range of the salt")
+			}
+
+			v = lo
+		}
+
+		if v < od.offset || v-od.offset > math.MaxInt64 {
+			return numbers, fmt.Errorf("number is out of range of the salt")
+		}
+
+		numbers = append(numbers, int64(v-od.offset))
+	}
+
+	return numbers, nil
+}
+
+// position of the symbol among sorted digits of the i-th position of a number,
+// -1 when it is not a digit there
+func (od *ordered[T]) position(i int, s T) int {
+	digits := od.digits[i]
+
+	j := sort.Search(len(digits), func(j int) bool { return digits[j] >= s })
+	if j < len(digits) && digits[j] == s {
+		return j
+	}
+
+	return -1
+}
+
+// initializeDigits of the order preserving mode from the whole alphabet, before
+// seps and guards are taken out of it. Every position of a number gets three
+// quarters of the alphabet, shuffled with the salt once more for each position
+func (o *Options) initializeDigits() error {
+	base := len(o.alphabet) - len(o.alphabet)/4
+
+	width := 0
+	for v := uint64(math.MaxUint64); v > 0; v /= uint64(base) {
+		width++
+	}
+
+	if width < o.Length {
+		width = o.Length
+	}
+
+	if maxLength := o.maxLength(); maxLength > 0 && maxLength < width {
+		return fmt.Errorf("MaxLength %d is smaller than %d characters of a number in order preserving mode", maxLength, width)
+	}
+
+	shuffled := append([]rune(nil), o.alphabet...)
+	o.digits = make([][]rune, width)
+
+	for i := range o.digits {
+		if len(o.salt) > 0 {
+			salt := append(append([]rune(nil), o.salt...), shuffled...)
+			shuffleInPlace(shuffled, salt[:len(shuffled)])
+		}
+
+		digits := append([]rune(nil), shuffled[:base]...)
+		sort.Slice(digits, func(i, j int) bool { return digits[i] < digits[j] })
+		o.digits[i] = digits
+	}
+
+	return nil
+}
+
+// orderedWidth - characters of every number in order preserving mode,
+// enough for any number shifted by the offset, but no less than Length
+func (o Options) orderedWidth() int {
+	return len(o.digits)
+}
+
+// orderedOffset derived from the salt, shifted numbers never exceed uint64
+func (o Options) orderedOffset() uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(o.Salt))
+
+	return h.Sum64() & math.MaxInt64
+}
@@ -0,0 +1,148 @@
+package hashids
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_OrderPreservingSortsLikeNumbers(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		name    string
+		options Options
+	}{
+		{"default alphabet", Options{Salt: "ordered salt", OrderPreserving: true}},
+		{"numeric", Options{Salt: "ordered salt", Alphabet: NumericAlphabet, OrderPreserving: true}},
+		{"prefix and length", Options{Salt: "ordered salt", Alphabet: Base58Alphabet, Length: 16, Prefix: "ord_", OrderPreserving: true}},
+		{"unicode", Options{Salt: "ordered salt", Alphabet: "абвгдежзийклмнопрстуфхцчшщ0123456789", OrderPreserving: true}},
+	}
+
+	for _, tc := range tt {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			h, err := New(tc.options)
+			assert.NoError(t, err)
+
+			rnd := rand.New(rand.NewSource(1))
+			numbers := []int64{0, 1, 2, 9, 10, 11, math.MaxInt64 - 1, math.MaxInt64}
+			for i := 0; i < 500; i++ {
+				numbers = append(numbers, rnd.Int63n(1<<uint(rnd.Intn(62)+1)))
+			}
+
+			sort.Slice(numbers, func(i, j int) bool { return numbers[i] < numbers[j] })
+
+			hashes := make([]string, len(numbers))
+			for i, n := range numbers {
+				hashes[i], err = h.Encode(n)
+				assert.NoError(t, err)
+
+				length, _ := h.EncodedLen(n)
+				assert.Equal(t, len([]rune(hashes[i])), length)
+
+				decoded, err := h.Decode(hashes[i]).FirstInt64()
+				assert.NoError(t, err)
+				assert.Equal(t, n, decoded)
+			}
+
+			assert.True(t, sort.StringsAreSorted(hashes))
+		})
+	}
+}
+
+func Test_OrderPreservingTuples(t *testing.T) {
+	t.Parallel()
+
+	h, _ := New(Options{Salt: "ordered salt", OrderPreserving: true})
+
+	tuples := [][]int64{{1}, {1, 0}, {1, 5}, {1, 5, 0}, {2, 0}, {2, 1}, {3}, {300, 2}}
+
+	hashes := make([]string, len(tuples))
+	for i, tuple := range tuples {
+		hashes[i], _ = h.Encode(tuple)
+
+		numbers, err := h.Decode(hashes[i]).Unwrap()
+		assert.NoError(t, err)
+		assert.Equal(t, tuple, numbers)
+	}
+
+	assert.True(t, sort.StringsAreSorted(hashes))
+}
+
+func Test_OrderPreservingIsSalted(t *testing.T) {
+	t.Parallel()
+
+	a, _ := New(Options{Salt: "first salt", OrderPreserving: true})
+	b, _ := New(Options{Salt: "second salt", OrderPreserving: true})
+
+	hashA, _ := a.Encode(1)
+	hashB, _ := b.Encode(1)
+	assert.NotEqual(t, hashA, hashB)
+	assert.Equal(t, 12, len(hashA))
+
+	// the salt selects the digits of every position
+	assert.NotEqual(t, a.options.digits[0], b.options.digits[0])
+	assert.NotEqual(t, a.options.digits[0], a.options.digits[1])
+	assert.Equal(t, 47, len(a.options.digits[0]))
+
+	stats, err := a.Stats(0, 1000)
+	assert.NoError(t, err)
+	assert.Equal(t, &LengthStats{Min: 12, Max: 12, Counts: map[int]int64{12: 1001}}, stats)
+
+	stats, err = a.Stats(0, math.MaxInt64)
+	assert.NoError(t, err)
+	assert.Equal(t, &LengthStats{Min: 12, Max: 12, Counts: map[int]int64{12: math.MaxInt64}}, stats)
+	assert.Equal(t, 24, a.MaxEncodedLen(2))
+	assert.Equal(t, int64(math.MaxInt64), a.MaxEncodableValue())
+}
+
+func Test_OrderPreservingErrors(t *testing.T) {
+	t.Parallel()
+
+	h, _ := New(Options{Salt: "ordered salt", OrderPreserving: true})
+	valid, _ := h.Encode(1)
+
+	var lowest, highest []rune
+	for _, digits := range h.options.digits {
+		lowest = append(lowest, digits[0])
+		highest = append(highest, digits[len(digits)-1])
+	}
+
+	// a character of the alphabet, but not a digit of the last position
+	unused := strings.TrimLeft(DefaultAlphabet, string(h.options.digits[11]))[:1]
+
+	tt := []struct {
+		name string
+		hash string
+		err  string
+	}{
+		{"empty", "", "hash length 0 must be a multiple of 12 in order preserving mode"},
+		{"length", valid[:11], "hash length 11 must be a multiple of 12 in order preserving mode"},
+		{"foreign character", valid[:11] + "-", "alphabet that was used for hashing was different"},
+		{"not a digit of the position", valid[:11] + unused, "alphabet that was used for hashing was different"},
+		{"below the offset", string(lowest), "number is out of range of the salt"},
+		{"above uint64", string(highest), "number is out of range of the salt"},
+	}
+
+	for _, tc := range tt {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			h, _ := New(Options{Salt: "ordered salt", OrderPreserving: true})
+			assert.EqualError(t, h.Decode(tc.hash).Err(), tc.err)
+		})
+	}
+
+	_, err := New(Options{OrderPreserving: true, Length: 4, MaxLength: 8})
+	assert.EqualError(t, err, "MaxLength 8 is smaller than 12 characters of a number in order preserving mode")
+}
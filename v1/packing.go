@@ -0,0 +1,136 @@
+package hashids
+
+import "fmt"
+
+// wordBits - bits of a non negative int64 a packed word can use
+const wordBits = 63
+
+// Packing of small numbers into as few words as their bit widths allow,
+// e.g. widths 2, 3 and 1 pack (3, 7, 1) into a single number. Words are
+// int64 numbers the hasher encodes as usual, there are deliberately no big
+// words: a word of more than 63 bits would need its own hash format, and
+// splitting it into int64 numbers costs only a separator per 63 bits
+type Packing struct {
+	widths []int
+	total  int
+}
+
+// NewPacking of fields with the widths in bits, 1 to 63 each
+func NewPacking(widths ...int) (*Packing, error) {
+	if len(widths) == 0 {
+		return nil, fmt.Errorf("packing must have at least 1 field")
+	}
+
+	p := &Packing{widths: append([]int(nil), widths...)}
+
+	for i, w := range widths {
+		if w < 1 || w > wordBits {
+			return nil, fmt.Errorf("width %d of field %d must be from 1 to %d bits", w, i, wordBits)
+		}
+
+		p.total += w
+	}
+
+	return p, nil
+}
+
+// Words - how many numbers are hashed
+func (p *Packing) Words() int {
+	return (p.total + wordBits - 1) / wordBits
+}
+
+// Pack the values, a value must fit into the width of its field
+func (p *Packing) Pack(values ...int64) ([]int64, error) {
+	if len(values) != len(p.widths) {
+		return nil, fmt.Errorf("expected %d values, got %d", len(p.widths), len(values))
+	}
+
+	words := make([]int64, p.Words())
+	offset := 0
+
+	for i, v := range values {
+		w := p.widths[i]
+		if v < 0 || v > mask(w) {
+			return nil, fmt.Errorf("value %d of field %d does not fit into %d bits", v, i, w)
+		}
+
+		for done := 0; done < w; {
+			shift := offset % wordBits
+			n := min(w-done, wordBits-shift)
+
+			words[offset/wordBits] |= (v >> uint(done) & mask(n)) << uint(shift)
+
+			done += n
+			offset += n
+		}
+	}
+
+	return words, nil
+}
+
+// Unpack the words back into the values
+func (p *Packing) Unpack(words []int64) ([]int64, error) {
+	if len(words) != p.Words() {
+		return nil, fmt.Errorf("expected %d packed numbers, got %d", p.Words(), len(words))
+	}
+
+	// unused high bits of the last word must be empty, so every
+	// tuple has a single hash
+	if last := p.total - wordBits*(len(words)-1); words[len(words)-1] > mask(last) {
+		return nil, fmt.Errorf("packed number %d has more than %d bits", words[len(words)-1], last)
+	}
+
+	values := make([]int64, len(p.widths))
+	offset := 0
+
+	for i, w := range p.widths {
+		for done := 0; done < w; {
+			shift := offset % wordBits
+			n := min(w-done, wordBits-shift)
+
+			values[i] |= (words[offset/wordBits] >> uint(shift) & mask(n)) << uint(done)
+
+			done += n
+			offset += n
+		}
+	}
+
+	return values, nil
+}
+
+// mask of n low bits
+func mask(n int) int64 {
+	return 1<<uint(n) - 1
+}
+
+// PackedHasher encodes tuples packed by bit widths,
+// like the Hasher it is not safe for concurrent use
+type PackedHasher struct {
+	h       *Hasher
+	packing *Packing
+}
+
+// WithPacking - hasher of tuples of small numbers
+func (h *Hasher) WithPacking(p *Packing) *PackedHasher {
+	return &PackedHasher{h: h, packing: p}
+}
+
+// Encode the tuple
+func (ph *PackedHasher) Encode(values ...int64) (string, error) {
+	words, err := ph.packing.Pack(values...)
+	if err != nil {
+		return "", err
+	}
+
+	return ph.h.Encode(words)
+}
+
+// Decode the hash into the tuple
+func (ph *PackedHasher) Decode(hash string) *DecodedResult {
+	words, err := ph.h.Decode(hash).Unwrap()
+	if err != nil {
+		return ph.h.result(nil, err)
+	}
+
+	return ph.h.result(ph.packing.Unpack(words))
+}
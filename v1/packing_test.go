@@ -0,0 +1,89 @@
+package hashids
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_PackedHashIsShorter(t *testing.T) {
+	t.Parallel()
+
+	h, _ := New(Options{Salt: "packing salt"})
+	p, err := NewPacking(2, 3, 1)
+	assert.NoError(t, err)
+
+	packed, err := h.WithPacking(p).Encode(3, 7, 1)
+	assert.NoError(t, err)
+
+	plain, _ := h.Encode(3, 7, 1)
+	assert.Less(t, len(packed), len(plain))
+
+	expected, _ := h.Encode(3 | 7<<2 | 1<<5)
+	assert.Equal(t, expected, packed)
+
+	values, err := h.WithPacking(p).Decode(packed).Unwrap()
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{3, 7, 1}, values)
+}
+
+func Test_PackingAcrossWords(t *testing.T) {
+	t.Parallel()
+
+	p, err := NewPacking(40, 40, 63, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, p.Words())
+
+	rnd := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 1000; i++ {
+		values := []int64{rnd.Int63n(1 << 40), rnd.Int63n(1 << 40), rnd.Int63(), rnd.Int63n(2)}
+
+		words, err := p.Pack(values...)
+		assert.NoError(t, err)
+
+		unpacked, err := p.Unpack(words)
+		assert.NoError(t, err)
+		assert.Equal(t, values, unpacked)
+	}
+
+	words, _ := p.Pack(1<<40-1, 1<<40-1, math.MaxInt64, 1)
+	assert.Equal(t, []int64{math.MaxInt64, math.MaxInt64, 1<<18 - 1}, words)
+}
+
+func Test_PackingErrors(t *testing.T) {
+	t.Parallel()
+
+	h, _ := New(Options{Salt: "packing salt"})
+	p, _ := NewPacking(2, 3, 1)
+	ph := h.WithPacking(p)
+
+	tooLarge, _ := h.Encode(1 << 6)
+	twoWords, _ := h.Encode(1, 2)
+
+	tt := []struct {
+		name string
+		err  func() error
+		msg  string
+	}{
+		{"width", func() error { _, err := NewPacking(2, 64); return err }, "width 64 of field 1 must be from 1 to 63 bits"},
+		{"no fields", func() error { _, err := NewPacking(); return err }, "packing must have at least 1 field"},
+		{"arity", func() error { _, err := ph.Encode(1, 2); return err }, "expected 3 values, got 2"},
+		{"does not fit", func() error { _, err := ph.Encode(4, 1, 1); return err }, "value 4 of field 0 does not fit into 2 bits"},
+		{"negative", func() error { _, err := ph.Encode(1, -1, 1); return err }, "value -1 of field 1 does not fit into 3 bits"},
+		{"too many bits", func() error { return ph.Decode(tooLarge).Err() }, "packed number 64 has more than 6 bits"},
+		{"words", func() error { return ph.Decode(twoWords).Err() }, "expected 1 packed numbers, got 2"},
+	}
+
+	for _, tc := range tt {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.EqualError(t, tc.err(), tc.msg)
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package hashids
+
+import (
+	"fmt"
+	"math"
+)
+
+// permutation of numbers applied before hashing and reversed after decoding,
+// it keeps its own buffers, so every codec gets a clone
+type permutation interface {
+	forward(v int64) (int64, error)
+	backward(v int64) (int64, error)
+	clone() permutation
+}
+
+// permutation from the options, nil if numbers are hashed as they are
+func (o Options) permutation() (permutation, error) {
+	if o.Key != nil {
+		return newFeistel(o.Key, o.domain())
+	}
+
+	if o.ScrambleKey != 0 {
+		return newScrambler(o.ScrambleKey, o.domain()), nil
+	}
+
+	return nil, nil
+}
+
+// domain - how many numbers starting from 0 have hashes that fit into
+// MaxLength, a single number is hashed as a lottery character followed
+// by its digits in the base of the alphabet, 0 means all of int64
+func (o Options) domain() uint64 {
+	maxLength := o.maxLength()
+	if maxLength == 0 {
+		return 0
+	}
+
+	base := uint64(len(o.alphabet))
+	d := uint64(1)
+	for i := 1; i < maxLength; i++ {
+		if d > math.MaxInt64/base {
+			return 0
+		}
+
+		d *= base
+	}
+
+	return d
+}
+
+// permuteAll appends permuted numbers to dst
+func permuteAll(dst []int64, numbers []int64, p permutation) ([]int64, error) {
+	for _, n := range numbers {
+		v, err := p.forward(n)
+		if err != nil {
+			return dst, err
+		}
+
+		dst = append(dst, v)
+	}
+
+	return dst, nil
+}
+
+// outOfDomain - error of a number that cannot be permuted
+func outOfDomain(v int64, domain uint64) error {
+	return fmt.Errorf("number %d does not fit into MaxLength, the largest is %d", v, domain-1)
+}
@@ -0,0 +1,188 @@
+package hashids
+
+import "fmt"
+
+// reference implementation of the original rune based algorithm,
+// the optimized codecs are checked for equivalence against it
+
+func referenceEncode(o Options, numbers []int64) (string, error) {
+	if len(numbers) == 0 {
+		return "", fmt.Errorf("cannot encode an empty slice of numbers")
+	}
+
+	for _, n := range numbers {
+		if n < 0 {
+			return "", fmt.Errorf("negative numbers like %d are not allowed", n)
+		}
+	}
+
+	alphabet := o.alphabetCopy()
+	numbersHashInt := createNumbersHashInt(numbers)
+	lottery := alphabet[numbersHashInt%int64(len(alphabet))]
+	salt := o.saltCopy()
+
+	result := []rune{lottery}
+	buf := make([]rune, 0, len(alphabet)+len(salt)+1)
+
+	for i, n := range numbers {
+		buf = buf[:1]
+		buf[0] = lottery
+		buf = append(buf, salt...)
+		buf = append(buf, alphabet...)
+		alphabet = referenceShuffle(alphabet, buf[:len(alphabet)])
+
+		hashSlice := referenceHash(n, alphabet)
+		result = append(result, hashSlice...)
+
+		if i < len(numbers)-1 {
+			n %= int64(hashSlice[0]) + int64(i)
+			result = append(result, o.seps[n%int64(len(o.seps))])
+		}
+	}
+
+	if len(result) < o.Length {
+		i := (numbersHashInt + int64(result[0])) % int64(len(o.guards))
+		result = append([]rune{o.guards[i]}, result...)
+
+		if len(result) < o.Length {
+			i := (numbersHashInt + int64(result[2])) % int64(len(o.guards))
+			result = append(result, o.guards[i])
+		}
+	}
+
+	middle := len(alphabet) / 2
+	for len(result) < o.Length {
+		alphabet = referenceShuffle(alphabet, alphabet)
+		result = append(alphabet[middle:], append(result, alphabet[:middle]...)...)
+		excess := len(result) - o.Length
+		if excess > 0 {
+			result = result[excess/2 : excess/2+o.Length]
+		}
+	}
+
+	return string(result), nil
+}
+
+func referenceDecode(o Options, input string) ([]int64, error) {
+	numbers := make([]int64, 0)
+	hashGroups := referenceSeparate([]rune(input), o.guards)
+	i := 0
+
+	if len(hashGroups) == 2 || len(hashGroups) == 3 {
+		i = 1
+	}
+
+	breakdown := hashGroups[i]
+
+	if len(breakdown) == 0 {
+		breakdown = hashGroups[0]
+	}
+
+	if len(breakdown) > 0 {
+		lottery := breakdown[0]
+		breakdown = breakdown[1:]
+		hashGroups = referenceSeparate(breakdown, o.seps)
+		alphabet := o.alphabetCopy()
+		for _, rs := range hashGroups {
+			buf := []rune{lottery}
+			buf = append(buf, o.salt...)
+			buf = append(buf, alphabet...)
+			alphabet = referenceShuffle(alphabet, buf[:len(alphabet)])
+			number, err := referenceUnhash(rs, alphabet)
+			if err != nil {
+				return nil, err
+			}
+			numbers = append(numbers, number)
+		}
+	}
+
+	check, err := referenceEncode(o, numbers)
+	if err != nil {
+		return nil, fmt.Errorf("error when trying to verify result: %v", err)
+	}
+
+	if check != input {
+		return nil, fmt.Errorf("mismatch between encoded and decoded values: %s -> %s, obtained result %v", o.Prefix+check, input, numbers)
+	}
+
+	return numbers, nil
+}
+
+func referenceHash(in int64, alphabet []rune) []rune {
+	out := make([]rune, 0)
+	alphabetLength := int64(len(alphabet))
+
+	for {
+		out = append(out, alphabet[in%alphabetLength])
+		in /= alphabetLength
+		if in == 0 {
+			break
+		}
+	}
+
+	for i := len(out)/2 - 1; i >= 0; i-- {
+		j := len(out) - 1 - i
+		out[i], out[j] = out[j], out[i]
+	}
+
+	return out
+}
+
+func referenceUnhash(in, alphabet []rune) (out int64, err error) {
+	for _, r := range in {
+		pos := -1
+		for i, s := range alphabet {
+			if r == s {
+				pos = i
+				break
+			}
+		}
+
+		if pos == -1 {
+			err = fmt.Errorf("alphabet that was used for hashing was different")
+			return
+		}
+
+		out = out*int64(len(alphabet)) + int64(pos)
+	}
+
+	return
+}
+
+func referenceSeparate(in, seps []rune) (out [][]rune) {
+	indicies := make([]int, 0)
+	for i, r := range in {
+		for _, s := range seps {
+			if r == s {
+				indicies = append(indicies, i)
+			}
+		}
+	}
+
+	left := in[:]
+	for _, idx := range indicies {
+		idx -= len(in) - len(left)
+		out = append(out, left[:idx])
+		left = left[idx+1:]
+	}
+
+	return append(out, left)
+}
+
+func referenceShuffle(in, salt []rune) []rune {
+	out := make([]rune, len(in))
+	copy(out, in)
+	if len(salt) == 0 {
+		return out
+	}
+
+	p, v := 0, 0
+	for i := len(in) - 1; i > 0; i-- {
+		p += int(salt[v])
+		j := (int(salt[v]) + v + p) % i
+		out[i], out[j] = out[j], out[i]
+		v = (v + 1) % len(salt)
+	}
+
+	return out
+}
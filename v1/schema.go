@@ -0,0 +1,353 @@
+package hashids
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Field of a schema
+type Field struct {
+	Name    string
+	Min     int64
+	Max     int64
+	Bounded bool
+}
+
+// Unbounded field, any non negative number
+func Unbounded(name string) Field {
+	return Field{Name: name}
+}
+
+// Bounded field, numbers from min to max inclusive
+func Bounded(name string, min, max int64) Field {
+	return Field{Name: name, Min: min, Max: max, Bounded: true}
+}
+
+// String representation, e.g. shard 0..1023
+func (f Field) String() string {
+	if !f.Bounded {
+		return f.Name
+	}
+
+	return fmt.Sprintf("%s %d..%d", f.Name, f.Min, f.Max)
+}
+
+func (f Field) check(v int64) error {
+	if f.Bounded && (v < f.Min || v > f.Max) {
+		return fmt.Errorf("field %s value %d is out of range %d..%d", f.Name, v, f.Min, f.Max)
+	}
+
+	return nil
+}
+
+// Schema - named fields of the numbers of a hash
+type Schema struct {
+	fields []Field
+	index  map[string]int
+}
+
+// NewSchema of the fields in the order of numbers in the hash
+func NewSchema(fields ...Field) (*Schema, error) {
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("schema must have at least 1 field")
+	}
+
+	s := &Schema{fields: make([]Field, len(fields)), index: make(map[string]int, len(fields))}
+
+	for i, f := range fields {
+		if f.Name == "" {
+			return nil, fmt.Errorf("field %d has no name", i)
+		}
+
+		if _, ok := s.index[f.Name]; ok {
+			return nil, fmt.Errorf("field %s is declared twice", f.Name)
+		}
+
+		if f.Bounded && (f.Min < 0 || f.Min > f.Max) {
+			return nil, fmt.Errorf("invalid range %d..%d of field %s", f.Min, f.Max, f.Name)
+		}
+
+		s.fields[i] = f
+		s.index[f.Name] = i
+	}
+
+	return s, nil
+}
+
+// ParseSchema of comma separated fields with optional ranges,
+// e.g. "tenant, shard 0..1023, doc"
+func ParseSchema(s string) (*Schema, error) {
+	var fields []Field
+
+	for _, part := range strings.Split(s, ",") {
+		words := strings.Fields(part)
+
+		switch len(words) {
+		case 1:
+			fields = append(fields, Unbounded(words[0]))
+		case 2:
+			bounds := strings.SplitN(words[1], "..", 2)
+			if len(bounds) != 2 {
+				return nil, fmt.Errorf("invalid range %s of field %s", words[1], words[0])
+			}
+
+			min, err := strconv.ParseInt(bounds[0], 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid range %s of field %s", words[1], words[0])
+			}
+
+			max, err := strconv.ParseInt(bounds[1], 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid range %s of field %s", words[1], words[0])
+			}
+
+			fields = append(fields, Bounded(words[0], min, max))
+		default:
+			return nil, fmt.Errorf("invalid field %q", strings.TrimSpace(part))
+		}
+	}
+
+	return NewSchema(fields...)
+}
+
+// Fields of the schema
+func (s *Schema) Fields() []Field {
+	return append([]Field(nil), s.fields...)
+}
+
+// String representation that ParseSchema accepts
+func (s *Schema) String() string {
+	fields := make([]string, len(s.fields))
+	for i, f := range s.fields {
+		fields[i] = f.String()
+	}
+
+	return strings.Join(fields, ", ")
+}
+
+// numbers of a map[string]int64, map[string]int or a struct in the schema order,
+// struct fields are matched by hashid tag or by name ignoring case
+func (s *Schema) numbers(v interface{}) ([]int64, error) {
+	numbers := make([]int64, len(s.fields))
+	found := make([]bool, len(s.fields))
+
+	set := func(name string, n int64) error {
+		i, ok := s.index[name]
+		if !ok {
+			return fmt.Errorf("unknown field %s", name)
+		}
+
+		numbers[i], found[i] = n, true
+
+		return nil
+	}
+
+	switch value := v.(type) {
+	case map[string]int64:
+		for name, n := range value {
+			if err := set(name, n); err != nil {
+				return nil, err
+			}
+		}
+	case map[string]int:
+		for name, n := range value {
+			if err := set(name, int64(n)); err != nil {
+				return nil, err
+			}
+		}
+	default:
+		if err := s.structNumbers(v, set); err != nil {
+			return nil, err
+		}
+	}
+
+	for i, f := range s.fields {
+		if !found[i] {
+			return nil, fmt.Errorf("missing field %s", f.Name)
+		}
+
+		if err := f.check(numbers[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	return numbers, nil
+}
+
+func (s *Schema) structNumbers(v interface{}, set func(name string, n int64) error) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Ptr && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+
+	if rv.Kind() != reflect.Struct {
+		return fmt.Errorf("expected a map or a struct, %T given", v)
+	}
+
+	// struct fields by the schema fields they map to
+	mapped := make(map[string]string)
+
+	t := rv.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+
+		name, err := s.fieldName(f)
+		if err != nil {
+			return err
+		}
+
+		if name == "" {
+			continue
+		}
+
+		if other, ok := mapped[name]; ok {
+			return fmt.Errorf("fields %s and %s map to the same schema field %s", other, f.Name, name)
+		}
+
+		mapped[name] = f.Name
+
+		n, err := intField(rv.Field(i), f.Name)
+		if err != nil {
+			return err
+		}
+
+		if err := set(name, n); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// fieldName in the schema of a struct field, empty when it is not in the schema,
+// it is named by a `hashid_field:"<name>"` tag, placed by a `hashid:"<position>"` tag
+// as for EncodeStruct, or matches the name of the struct field ignoring case,
+// tags naming fields that are not in the schema are errors
+func (s *Schema) fieldName(f reflect.StructField) (string, error) {
+	if tag, ok := f.Tag.Lookup("hashid_field"); ok {
+		if tag == "-" {
+			return "", nil
+		}
+
+		if _, ok := s.index[tag]; !ok {
+			return "", fmt.Errorf("unknown hashid_field %q of field %s for schema %s", tag, f.Name, s)
+		}
+
+		return tag, nil
+	}
+
+	if tag, ok := f.Tag.Lookup("hashid"); ok {
+		if tag == "-" {
+			return "", nil
+		}
+
+		position, err := strconv.Atoi(tag)
+		if err != nil || position < 0 || position >= len(s.fields) {
+			return "", fmt.Errorf("invalid hashid tag %q of field %s for schema %s", tag, f.Name, s)
+		}
+
+		return s.fields[position].Name, nil
+	}
+
+	for _, field := range s.fields {
+		if strings.EqualFold(field.Name, f.Name) {
+			return field.Name, nil
+		}
+	}
+
+	return "", nil
+}
+
+// SchemaHasher encodes and decodes numbers of a schema,
+// like the Hasher it is not safe for concurrent use
+type SchemaHasher struct {
+	h      *Hasher
+	schema *Schema
+}
+
+// WithSchema - hasher of numbers with named fields
+func (h *Hasher) WithSchema(s *Schema) *SchemaHasher {
+	return &SchemaHasher{h: h, schema: s}
+}
+
+// Encode a map[string]int64, map[string]int or a struct,
+// every field of the schema must be present and in its range
+func (sh *SchemaHasher) Encode(v interface{}) (string, error) {
+	numbers, err := sh.schema.numbers(v)
+	if err != nil {
+		return "", err
+	}
+
+	return sh.h.Encode(numbers)
+}
+
+// Decode the hash, its numbers must match the fields and their ranges
+func (sh *SchemaHasher) Decode(hash string) *SchemaResult {
+	numbers, err := sh.h.Decode(hash).Unwrap()
+	if err != nil {
+		return &SchemaResult{schema: sh.schema, err: err}
+	}
+
+	if len(numbers) != len(sh.schema.fields) {
+		err := fmt.Errorf("hash contains %d numbers, but schema has %d fields", len(numbers), len(sh.schema.fields))
+		return &SchemaResult{schema: sh.schema, err: err}
+	}
+
+	for i, f := range sh.schema.fields {
+		if err := f.check(numbers[i]); err != nil {
+			return &SchemaResult{schema: sh.schema, err: err}
+		}
+	}
+
+	return &SchemaResult{schema: sh.schema, numbers: numbers}
+}
+
+// SchemaResult of the decoded hash
+type SchemaResult struct {
+	schema  *Schema
+	numbers []int64
+	err     error
+}
+
+// Err - get the error of the result
+func (r *SchemaResult) Err() error {
+	return r.err
+}
+
+// Get the number of the named field
+func (r *SchemaResult) Get(name string) (int64, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+
+	i, ok := r.schema.index[name]
+	if !ok {
+		return 0, fmt.Errorf("unknown field %s", name)
+	}
+
+	return r.numbers[i], nil
+}
+
+// Map of field names to numbers
+func (r *SchemaResult) Map() (map[string]int64, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	m := make(map[string]int64, len(r.numbers))
+	for i, f := range r.schema.fields {
+		m[f.Name] = r.numbers[i]
+	}
+
+	return m, nil
+}
+
+// Unwrap the raw numbers in the schema order and error
+func (r *SchemaResult) Unwrap() ([]int64, error) {
+	return r.numbers, r.err
+}
@@ -0,0 +1,158 @@
+package hashids
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestSchema(t *testing.T) *Schema {
+	s, err := ParseSchema("tenant, shard 0..1023, doc")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return s
+}
+
+func Test_ParseSchema(t *testing.T) {
+	t.Parallel()
+
+	s := newTestSchema(t)
+	assert.Equal(t, []Field{Unbounded("tenant"), Bounded("shard", 0, 1023), Unbounded("doc")}, s.Fields())
+	assert.Equal(t, "tenant, shard 0..1023, doc", s.String())
+
+	tt := []struct {
+		schema string
+		err    string
+	}{
+		{"a, a", "field a is declared twice"},
+		{"a 5..1", "invalid range 5..1 of field a"},
+		{"a -1..5", "invalid range -1..5 of field a"},
+		{"a 1-5", "invalid range 1-5 of field a"},
+		{"a 1..b", "invalid range 1..b of field a"},
+		{"a 1..5 b", `invalid field "a 1..5 b"`},
+		{"a,,b", `invalid field ""`},
+	}
+
+	for _, tc := range tt {
+		tc := tc
+
+		t.Run(tc.schema, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := ParseSchema(tc.schema)
+			assert.EqualError(t, err, tc.err)
+		})
+	}
+}
+
+func Test_SchemaEncodeAndDecode(t *testing.T) {
+	t.Parallel()
+
+	h, _ := New(Options{Salt: "schema salt", Length: 8})
+	sh := h.WithSchema(newTestSchema(t))
+
+	expected, _ := h.Encode(5, 1000, 42)
+
+	type ref struct {
+		Tenant   int64
+		Shard    uint16
+		Document int `hashid_field:"doc"`
+		Name     string
+	}
+
+	// written for EncodeStruct
+	type positional struct {
+		DocID    int64 `hashid:"2"`
+		TenantID int64 `hashid:"0"`
+		ShardID  int64 `hashid:"1"`
+	}
+
+	for _, v := range []interface{}{
+		map[string]int64{"tenant": 5, "shard": 1000, "doc": 42},
+		map[string]int{"doc": 42, "shard": 1000, "tenant": 5},
+		ref{Tenant: 5, Shard: 1000, Document: 42, Name: "ignored"},
+		&ref{Tenant: 5, Shard: 1000, Document: 42},
+		positional{TenantID: 5, ShardID: 1000, DocID: 42},
+	} {
+		hash, err := sh.Encode(v)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, hash)
+	}
+
+	hash, err := h.EncodeStruct(positional{TenantID: 5, ShardID: 1000, DocID: 42})
+	assert.NoError(t, err)
+	assert.Equal(t, expected, hash)
+
+	result := sh.Decode(expected)
+	assert.NoError(t, result.Err())
+
+	shard, err := result.Get("shard")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1000), shard)
+
+	m, err := result.Map()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int64{"tenant": 5, "shard": 1000, "doc": 42}, m)
+
+	_, err = result.Get("unknown")
+	assert.EqualError(t, err, "unknown field unknown")
+}
+
+func Test_SchemaErrors(t *testing.T) {
+	t.Parallel()
+
+	h, _ := New(Options{Salt: "schema salt"})
+	sh := h.WithSchema(newTestSchema(t))
+
+	pair, _ := h.Encode(1, 2)
+	outOfRange, _ := h.Encode(1, 2000, 3)
+
+	tt := []struct {
+		name string
+		err  func() error
+		msg  string
+	}{
+		{"missing field", func() error { _, err := sh.Encode(map[string]int64{"tenant": 1, "doc": 2}); return err }, "missing field shard"},
+		{"unknown field", func() error {
+			_, err := sh.Encode(map[string]int64{"tenant": 1, "shard": 1, "doc": 2, "x": 1})
+			return err
+		}, "unknown field x"},
+		{"encode out of range", func() error { _, err := sh.Encode(map[string]int64{"tenant": 1, "shard": 2000, "doc": 2}); return err }, "field shard value 2000 is out of range 0..1023"},
+		{"not a map or struct", func() error { _, err := sh.Encode([]int64{1, 2, 3}); return err }, "expected a map or a struct, []int64 given"},
+		{"invalid position", func() error {
+			_, err := sh.Encode(struct {
+				ID int64 `hashid:"3"`
+			}{})
+			return err
+		}, `invalid hashid tag "3" of field ID for schema tenant, shard 0..1023, doc`},
+		{"unknown hashid_field", func() error {
+			_, err := sh.Encode(struct {
+				Tenant int64 `hashid_field:"tenat"`
+			}{})
+			return err
+		}, `unknown hashid_field "tenat" of field Tenant for schema tenant, shard 0..1023, doc`},
+		{"same schema field", func() error {
+			_, err := sh.Encode(struct {
+				Tenant   int64
+				TenantID int64 `hashid_field:"tenant"`
+				Shard    int64
+				Doc      int64
+			}{})
+			return err
+		}, "fields Tenant and TenantID map to the same schema field tenant"},
+		{"decode arity", func() error { return sh.Decode(pair).Err() }, "hash contains 2 numbers, but schema has 3 fields"},
+		{"decode out of range", func() error { _, err := sh.Decode(outOfRange).Get("tenant"); return err }, "field shard value 2000 is out of range 0..1023"},
+	}
+
+	for _, tc := range tt {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.EqualError(t, tc.err(), tc.msg)
+		})
+	}
+}
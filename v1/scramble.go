@@ -0,0 +1,129 @@
+package hashids
+
+import (
+	"fmt"
+	"math/bits"
+)
+
+// scrambleRounds of multiplication and xorshift, two are enough
+// for neighbouring numbers to differ in about half of their bits
+const scrambleRounds = 2
+
+// scrambler - keyed bijection of numbers from 0 to domain-1, adds the key,
+// multiplies by odd constants and folds high bits into low ones modulo
+// the smallest power of two that covers the domain, with cycle walking
+// for values outside of the domain, it is fast, but not encryption
+type scrambler struct {
+	domain  uint64
+	mask    uint64
+	shift   uint
+	add     uint64
+	mul     [scrambleRounds]uint64
+	inverse [scrambleRounds]uint64
+}
+
+// newScrambler with a key, domain 0 means all of int64
+func newScrambler(key uint64, domain uint64) *scrambler {
+	if domain == 0 {
+		domain = 1 << 63
+	}
+
+	b := uint(bits.Len64(domain - 1))
+	if b == 0 {
+		b = 1
+	}
+
+	s := &scrambler{
+		domain: domain,
+		mask:   1<<b - 1,
+		shift:  (b + 1) / 2,
+		add:    key,
+	}
+
+	state := key
+	for i := range s.mul {
+		state = splitmix64(state)
+		s.mul[i] = state | 1
+		s.inverse[i] = inverseOdd(s.mul[i])
+	}
+
+	return s
+}
+
+func (s *scrambler) clone() permutation {
+	return s
+}
+
+func (s *scrambler) forward(v int64) (int64, error) {
+	if uint64(v) >= s.domain {
+		return 0, outOfDomain(v, s.domain)
+	}
+
+	x := uint64(v)
+	for {
+		x = s.scramble(x)
+		if x < s.domain {
+			return int64(x), nil
+		}
+	}
+}
+
+func (s *scrambler) backward(v int64) (int64, error) {
+	if v < 0 || uint64(v) >= s.domain {
+		return 0, fmt.Errorf("number %d was not scrambled with the ScrambleKey", v)
+	}
+
+	x := uint64(v)
+	for {
+		x = s.unscramble(x)
+		if x < s.domain {
+			return int64(x), nil
+		}
+	}
+}
+
+func (s *scrambler) scramble(x uint64) uint64 {
+	x = (x + s.add) & s.mask
+
+	for i := range s.mul {
+		x = x * s.mul[i] & s.mask
+		x ^= x >> s.shift
+	}
+
+	return x
+}
+
+func (s *scrambler) unscramble(x uint64) uint64 {
+	for i := len(s.mul) - 1; i >= 0; i-- {
+		// x ^ x>>shift is undone by repeating it, each time
+		// another shift worth of high bits becomes right
+		y := x
+		for done := s.shift; done < 64; done += s.shift {
+			x = y ^ x>>s.shift
+		}
+
+		x = x * s.inverse[i] & s.mask
+	}
+
+	return (x - s.add) & s.mask
+}
+
+// inverseOdd - multiplicative inverse of an odd number modulo 2^64,
+// by Newton's method, every step doubles the count of right bits
+func inverseOdd(a uint64) uint64 {
+	inv := a
+	for i := 0; i < 5; i++ {
+		inv *= 2 - a*inv
+	}
+
+	return inv
+}
+
+// splitmix64 - the next state of a splitmix generator
+func splitmix64(x uint64) uint64 {
+	x += 0x9e3779b97f4a7c15
+	x = (x ^ x>>30) * 0xbf58476d1ce4e5b9
+	x = (x ^ x>>27) * 0x94d049bb133111eb
+
+	return x ^ x>>31
+}
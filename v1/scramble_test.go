@@ -0,0 +1,121 @@
+package hashids
+
+import (
+	"math"
+	"math/bits"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ScrambleIsAPermutationOfTheDomain(t *testing.T) {
+	t.Parallel()
+
+	for _, domain := range []uint64{1, 2, 3, 10, 1000, 4096} {
+		s := newScrambler(42, domain)
+
+		seen := make(map[int64]bool)
+		for v := int64(0); v < int64(domain); v++ {
+			e, err := s.forward(v)
+			assert.NoError(t, err)
+			assert.True(t, e >= 0 && uint64(e) < domain)
+			assert.False(t, seen[e])
+			seen[e] = true
+
+			d, err := s.backward(e)
+			assert.NoError(t, err)
+			assert.Equal(t, v, d)
+		}
+	}
+
+	s := newScrambler(42, 0)
+	rnd := rand.New(rand.NewSource(1))
+	for _, v := range append([]int64{0, 1, math.MaxInt64}, rnd.Int63(), rnd.Int63(), rnd.Int63()) {
+		e, err := s.forward(v)
+		assert.NoError(t, err)
+
+		d, err := s.backward(e)
+		assert.NoError(t, err)
+		assert.Equal(t, v, d)
+	}
+}
+
+func Test_ScrambleDecorrelatesNeighbours(t *testing.T) {
+	t.Parallel()
+
+	s := newScrambler(0xdeadbeef, 0)
+
+	// neighbouring numbers differ in about half of their 63 bits
+	total := 0
+	prev, _ := s.forward(0)
+	for v := int64(1); v <= 10000; v++ {
+		next, _ := s.forward(v)
+		total += bits.OnesCount64(uint64(prev ^ next))
+		prev = next
+	}
+
+	average := float64(total) / 10000
+	assert.Greater(t, average, 28.0)
+	assert.Less(t, average, 35.0)
+}
+
+func Test_ScrambledHashes(t *testing.T) {
+	t.Parallel()
+
+	h, err := New(Options{Salt: "scramble salt", ScrambleKey: 12345, Length: 8})
+	assert.NoError(t, err)
+
+	p, _ := New(Options{Salt: "scramble salt", Length: 8})
+
+	// hashes of consecutive numbers have no common prefix after the lottery
+	shared := 0
+	prev, _ := h.Encode(1)
+	for n := int64(2); n <= 1000; n++ {
+		hash, err := h.Encode(n)
+		assert.NoError(t, err)
+
+		scrambled, _ := h.permutation().forward(n)
+		expected, _ := p.Encode(scrambled)
+		assert.Equal(t, expected, hash)
+
+		decoded, err := h.Decode(hash).FirstInt64()
+		assert.NoError(t, err)
+		assert.Equal(t, n, decoded)
+
+		if hash[:3] == prev[:3] {
+			shared++
+		}
+
+		prev = hash
+	}
+
+	assert.Less(t, shared, 10)
+
+	fit, _ := New(Options{ScrambleKey: 1, Length: 4, MaxLength: 4})
+	for n := fit.MaxEncodableValue() - 100; n <= fit.MaxEncodableValue(); n++ {
+		hash, err := fit.Encode(n)
+		assert.NoError(t, err)
+		assert.Equal(t, 4, len(hash))
+	}
+}
+
+func Test_InvalidScrambleKey(t *testing.T) {
+	t.Parallel()
+
+	_, err := New(Options{ScrambleKey: 1, Key: testKey})
+	assert.EqualError(t, err, "Key and ScrambleKey cannot be used together")
+
+	_, err = New(Options{ScrambleKey: 1, OrderPreserving: true})
+	assert.EqualError(t, err, "ScrambleKey cannot be used in order preserving mode")
+}
+
+func Benchmark_EncodeScrambled(b *testing.B) {
+	h, _ := New(Options{Salt: "scramble salt", ScrambleKey: 12345, Length: 16})
+	dst := make([]byte, 0, 64)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		dst, _ = h.AppendEncode(dst[:0], int64(i))
+	}
+}
@@ -0,0 +1,42 @@
+package hashids
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strconv"
+)
+
+// Value implements driver.Valuer, the ID is stored as an integer
+func (id ID[K]) Value() (driver.Value, error) {
+	return int64(id), nil
+}
+
+// Scan implements sql.Scanner from an integer column,
+// use *ID for nullable columns
+func (id *ID[K]) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case int64:
+		*id = ID[K](v)
+		return nil
+	case []byte:
+		return id.scanString(string(v))
+	case string:
+		return id.scanString(v)
+	case nil:
+		return &IDError{Kind: kindName[K](), Err: fmt.Errorf("cannot scan NULL, use a pointer for nullable columns")}
+	}
+
+	return &IDError{Kind: kindName[K](), Err: fmt.Errorf("cannot scan %T", src)}
+}
+
+// scanString of drivers that return integers as text
+func (id *ID[K]) scanString(s string) error {
+	v, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return &IDError{Kind: kindName[K](), Input: s, Err: fmt.Errorf("not an integer")}
+	}
+
+	*id = ID[K](v)
+
+	return nil
+}
@@ -0,0 +1,144 @@
+package hashids
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeDriver keeps rows of a single table in memory, the query
+// is ignored, Exec inserts its args as a row and Query returns all rows
+type fakeDriver struct {
+	mu   sync.Mutex
+	rows [][]driver.Value
+}
+
+func (d *fakeDriver) Open(string) (driver.Conn, error) { return &fakeConn{d}, nil }
+
+type fakeConn struct{ d *fakeDriver }
+
+func (c *fakeConn) Prepare(string) (driver.Stmt, error) { return &fakeStmt{c.d}, nil }
+func (c *fakeConn) Close() error                        { return nil }
+func (c *fakeConn) Begin() (driver.Tx, error)           { return nil, fmt.Errorf("not supported") }
+
+type fakeStmt struct{ d *fakeDriver }
+
+func (s *fakeStmt) Close() error  { return nil }
+func (s *fakeStmt) NumInput() int { return -1 }
+
+func (s *fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.d.mu.Lock()
+	defer s.d.mu.Unlock()
+
+	s.d.rows = append(s.d.rows, args)
+
+	return driver.RowsAffected(1), nil
+}
+
+func (s *fakeStmt) Query([]driver.Value) (driver.Rows, error) {
+	s.d.mu.Lock()
+	defer s.d.mu.Unlock()
+
+	return &fakeRows{rows: append([][]driver.Value(nil), s.d.rows...)}, nil
+}
+
+type fakeRows struct{ rows [][]driver.Value }
+
+func (r *fakeRows) Columns() []string { return []string{"id", "user_id", "parent"} }
+func (r *fakeRows) Close() error      { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+
+	return nil
+}
+
+func init() {
+	sql.Register("hashids-fake", &fakeDriver{})
+}
+
+func Test_IDStoredAsInteger(t *testing.T) {
+	db, err := sql.Open("hashids-fake", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	parent := ID[orderKind](3)
+	in := []order{{ID: 7, UserID: 42, Parent: &parent}, {ID: 8, UserID: 43}}
+
+	for _, o := range in {
+		if _, err := db.Exec("INSERT INTO orders VALUES (?, ?, ?)", o.ID, o.UserID, o.Parent); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	rows, err := db.Query("SELECT id, user_id, parent FROM orders")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	var out []order
+	for rows.Next() {
+		var o order
+		if err := rows.Scan(&o.ID, &o.UserID, &o.Parent); err != nil {
+			t.Fatal(err)
+		}
+
+		out = append(out, o)
+	}
+
+	assert.NoError(t, rows.Err())
+	assert.Equal(t, in, out)
+
+	expected, _ := json.Marshal(in)
+	actual, _ := json.Marshal(out)
+	assert.Equal(t, string(expected), string(actual))
+}
+
+func Test_IDScan(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		name string
+		src  interface{}
+		id   ID[userKind]
+		err  string
+	}{
+		{"int64", int64(42), 42, ""},
+		{"text", []byte("42"), 42, ""},
+		{"string", "42", 42, ""},
+		{"not an integer", "usr_abc", 0, `invalid userKind id "usr_abc": not an integer`},
+		{"null", nil, 0, "invalid userKind id: cannot scan NULL, use a pointer for nullable columns"},
+		{"float", 4.2, 0, "invalid userKind id: cannot scan float64"},
+	}
+
+	for _, tc := range tt {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			var id ID[userKind]
+			err := id.Scan(tc.src)
+
+			if tc.err != "" {
+				assert.EqualError(t, err, tc.err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.id, id)
+		})
+	}
+}
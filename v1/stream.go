@@ -0,0 +1,297 @@
+package hashids
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// Encoder writes hashes line by line, numbers of a single hash are comma separated in the input
+type Encoder struct {
+	w       *bufio.Writer
+	h       *Hasher
+	onError func(*LineError)
+	failed  int
+
+	numbers []int64
+	buf     []byte
+}
+
+// NewEncoder writing hashes to w
+func NewEncoder(w io.Writer, h *Hasher) *Encoder {
+	return &Encoder{
+		w: bufio.NewWriter(w),
+		h: h,
+	}
+}
+
+// OnError is called with every line that could not be encoded
+func (e *Encoder) OnError(f func(*LineError)) *Encoder {
+	e.onError = f
+
+	return e
+}
+
+// Failed lines count
+func (e *Encoder) Failed() int {
+	return e.failed
+}
+
+// Encode numbers and write the hash as a line, call Flush when done
+func (e *Encoder) Encode(numbers ...int64) error {
+	var err error
+	if e.buf, err = e.h.AppendEncode(e.buf[:0], numbers...); err != nil {
+		return err
+	}
+
+	return e.writeLine()
+}
+
+// Flush buffered hashes to the underlying writer
+func (e *Encoder) Flush() error {
+	return e.w.Flush()
+}
+
+// ReadFrom reads lines of comma separated numbers and writes a hash for each of them,
+// a line which cannot be encoded is reported to OnError and left empty in the output,
+// blank lines are not errors and stay blank
+func (e *Encoder) ReadFrom(r io.Reader) (int64, error) {
+	cr := &countingReader{r: r}
+	lines := NewLineReader(cr)
+
+	for lines.Scan() {
+		e.buf = e.buf[:0]
+		if lines.Blank() {
+			if err := e.writeLine(); err != nil {
+				return cr.n, err
+			}
+
+			continue
+		}
+
+		var err error
+		if e.numbers, err = appendNumbers(e.numbers[:0], lines.Bytes()); err == nil {
+			e.buf, err = e.h.AppendEncode(e.buf[:0], e.numbers...)
+		}
+
+		if err != nil {
+			e.failed++
+			if e.onError != nil {
+				e.onError(lines.Error(err))
+			}
+
+			e.buf = e.buf[:0]
+		}
+
+		if err := e.writeLine(); err != nil {
+			return cr.n, err
+		}
+	}
+
+	if err := lines.Err(); err != nil {
+		return cr.n, err
+	}
+
+	return cr.n, e.Flush()
+}
+
+// ParseNumbers separated by commas as in the lines read by an Encoder
+func ParseNumbers(s string) ([]int64, error) {
+	return appendNumbers(nil, []byte(s))
+}
+
+func appendNumbers(dst []int64, line []byte) ([]int64, error) {
+	for _, field := range bytes.Split(line, []byte(",")) {
+		field = bytes.TrimSpace(field)
+
+		n, err := strconv.ParseInt(string(field), 10, 64)
+		if err != nil {
+			return dst, fmt.Errorf("invalid number %q", field)
+		}
+
+		dst = append(dst, n)
+	}
+
+	return dst, nil
+}
+
+// writeLine from e.buf
+func (e *Encoder) writeLine() error {
+	e.buf = append(e.buf, '\n')
+	_, err := e.w.Write(e.buf)
+
+	return err
+}
+
+// Decoder reads hashes line by line
+type Decoder struct {
+	lines   *LineReader
+	h       *Hasher
+	onError func(*LineError)
+	failed  int
+
+	buf []byte
+}
+
+// NewDecoder reading hashes from r
+func NewDecoder(r io.Reader, h *Hasher) *Decoder {
+	return &Decoder{
+		lines: NewLineReader(r),
+		h:     h,
+	}
+}
+
+// OnError is called with every line that could not be decoded
+func (d *Decoder) OnError(f func(*LineError)) *Decoder {
+	d.onError = f
+
+	return d
+}
+
+// Failed lines count
+func (d *Decoder) Failed() int {
+	return d.failed
+}
+
+// Decode the next line skipping blank ones, returns io.EOF at the end of the input
+// and *LineError when the line is not a valid hash
+func (d *Decoder) Decode() ([]int64, error) {
+	for {
+		if !d.lines.Scan() {
+			if err := d.lines.Err(); err != nil {
+				return nil, err
+			}
+
+			return nil, io.EOF
+		}
+
+		if !d.lines.Blank() {
+			return d.decode()
+		}
+	}
+}
+
+// decode the current line, the error is a *LineError
+func (d *Decoder) decode() ([]int64, error) {
+	numbers, err := d.h.Decode(d.lines.Text()).Unwrap()
+	if err != nil {
+		return nil, d.lines.Error(err)
+	}
+
+	return numbers, nil
+}
+
+// WriteTo writes decoded numbers of every line comma separated,
+// a line which cannot be decoded is reported to OnError and left empty in the output,
+// blank lines are not errors and stay blank
+func (d *Decoder) WriteTo(w io.Writer) (int64, error) {
+	bw := bufio.NewWriter(w)
+	written := int64(0)
+
+	for d.lines.Scan() {
+		var numbers []int64
+		var err error
+		if !d.lines.Blank() {
+			numbers, err = d.decode()
+		}
+
+		d.buf = d.buf[:0]
+
+		// decode only fails with a *LineError
+		if err != nil {
+			d.failed++
+			if d.onError != nil {
+				d.onError(err.(*LineError))
+			}
+		}
+
+		for i, n := range numbers {
+			if i > 0 {
+				d.buf = append(d.buf, ',')
+			}
+			d.buf = strconv.AppendInt(d.buf, n, 10)
+		}
+
+		d.buf = append(d.buf, '\n')
+
+		n, err := bw.Write(d.buf)
+		written += int64(n)
+		if err != nil {
+			return written, err
+		}
+	}
+
+	if err := d.lines.Err(); err != nil {
+		return written, err
+	}
+
+	return written, bw.Flush()
+}
+
+// LineReader reads lines without surrounding spaces and counts them,
+// Encoder and Decoder read their input with it
+type LineReader struct {
+	s    *bufio.Scanner
+	line int
+	text []byte
+}
+
+// NewLineReader reading from r
+func NewLineReader(r io.Reader) *LineReader {
+	return &LineReader{s: bufio.NewScanner(r)}
+}
+
+// Scan the next line, false at the end of the input or when reading failed
+func (l *LineReader) Scan() bool {
+	if !l.s.Scan() {
+		return false
+	}
+
+	l.line++
+	l.text = bytes.TrimSpace(l.s.Bytes())
+
+	return true
+}
+
+// Bytes of the current line, valid until the next Scan
+func (l *LineReader) Bytes() []byte {
+	return l.text
+}
+
+// Blank - whether the current line is empty or holds only spaces
+func (l *LineReader) Blank() bool {
+	return len(l.text) == 0
+}
+
+// Text of the current line
+func (l *LineReader) Text() string {
+	return string(l.text)
+}
+
+// Line number of the current line starting from 1
+func (l *LineReader) Line() int {
+	return l.line
+}
+
+// Err of reading, nil at the end of the input
+func (l *LineReader) Err() error {
+	return l.s.Err()
+}
+
+// Error of the current line
+func (l *LineReader) Error(err error) *LineError {
+	return &LineError{Line: l.line, Input: l.Text(), Err: err}
+}
+
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
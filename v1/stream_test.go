@@ -0,0 +1,121 @@
+package hashids
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_EncoderAndDecoderRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	h, _ := New(DefaultOptions("test salt"))
+
+	input := "1\n2, 3\n\nabc\n-5\n45,434,1313,99\n"
+
+	var encoded bytes.Buffer
+	var encodeErrs []*LineError
+
+	enc := NewEncoder(&encoded, h).OnError(func(err *LineError) {
+		encodeErrs = append(encodeErrs, err)
+	})
+
+	n, err := enc.ReadFrom(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, int64(len(input)), n)
+	assert.Equal(t, 2, enc.Failed())
+	assert.Equal(t, 2, len(encodeErrs))
+	assert.Equal(t, []int{4, 5}, []int{encodeErrs[0].Line, encodeErrs[1].Line})
+	assert.Equal(t, `line 4: invalid number "abc"`, encodeErrs[0].Error())
+	assert.Equal(t, "-5", encodeErrs[1].Input)
+
+	lines := strings.Split(encoded.String(), "\n")
+	assert.Equal(t, 7, len(lines))
+	assert.Equal(t, "", lines[2])
+
+	var decoded bytes.Buffer
+	var decodeErrs []*LineError
+
+	dec := NewDecoder(strings.NewReader(encoded.String()+"invalid\n"), h).OnError(func(err *LineError) {
+		decodeErrs = append(decodeErrs, err)
+	})
+
+	if _, err := dec.WriteTo(&decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, "1\n2,3\n\n\n\n45,434,1313,99\n\n", decoded.String())
+	assert.Equal(t, 1, dec.Failed())
+	assert.Equal(t, 7, decodeErrs[0].Line)
+	assert.Equal(t, "invalid", decodeErrs[0].Input)
+}
+
+func Test_EncoderEncodeAndDecoderDecode(t *testing.T) {
+	t.Parallel()
+
+	h, _ := New(DefaultOptions("test salt"))
+
+	var buf bytes.Buffer
+	enc := NewEncoder(&buf, h)
+
+	assert.Nil(t, enc.Encode(1, 2, 3))
+	assert.Nil(t, enc.Encode(100500))
+	assert.Equal(t, "negative numbers like -1 are not allowed", enc.Encode(-1).Error())
+	assert.Nil(t, enc.Flush())
+
+	buf.WriteString("\n  \nbad hash\n\n")
+
+	dec := NewDecoder(&buf, h)
+
+	numbers, err := dec.Decode()
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{1, 2, 3}, numbers)
+
+	numbers, err = dec.Decode()
+	assert.Nil(t, err)
+	assert.Equal(t, []int64{100500}, numbers)
+
+	_, err = dec.Decode()
+
+	var lineErr *LineError
+	assert.True(t, errors.As(err, &lineErr))
+	assert.Equal(t, 5, lineErr.Line)
+
+	_, err = dec.Decode()
+	assert.Equal(t, io.EOF, err)
+}
+
+func Test_LineReaderAndParseNumbers(t *testing.T) {
+	t.Parallel()
+
+	lines := NewLineReader(strings.NewReader(" 1, 2 \n\nx\n"))
+
+	var parsed [][]int64
+	var errs []string
+
+	for lines.Scan() {
+		if lines.Blank() {
+			continue
+		}
+
+		numbers, err := ParseNumbers(lines.Text())
+		if err != nil {
+			errs = append(errs, lines.Error(err).Error())
+			continue
+		}
+
+		parsed = append(parsed, numbers)
+	}
+
+	assert.Nil(t, lines.Err())
+	assert.Equal(t, 3, lines.Line())
+	assert.Equal(t, [][]int64{{1, 2}}, parsed)
+	assert.Equal(t, []string{`line 3: invalid number "x"`}, errs)
+}
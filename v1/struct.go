@@ -0,0 +1,171 @@
+package hashids
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"sync"
+)
+
+// structFields - parsed struct types, field indexes ordered by position
+var structFields sync.Map
+
+// integerKinds of fields, true for signed ones
+var integerKinds = map[reflect.Kind]bool{
+	reflect.Int: true, reflect.Int8: true, reflect.Int16: true, reflect.Int32: true, reflect.Int64: true,
+	reflect.Uint: false, reflect.Uint8: false, reflect.Uint16: false, reflect.Uint32: false, reflect.Uint64: false,
+}
+
+// intField value of an integer field
+func intField(f reflect.Value, name string) (int64, error) {
+	signed, ok := integerKinds[f.Kind()]
+	if !ok {
+		return 0, fmt.Errorf("field %s must be an integer, %s given", name, f.Type())
+	}
+
+	if signed {
+		return f.Int(), nil
+	}
+
+	if f.Uint() > math.MaxInt64 {
+		return 0, fmt.Errorf("field %s value %d overflows int64", name, f.Uint())
+	}
+
+	return int64(f.Uint()), nil
+}
+
+// EncodeStruct encodes integer fields tagged with `hashid:"<position>"`,
+// positions must start at 0 and have no gaps
+func (h *Hasher) EncodeStruct(v interface{}) (string, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Ptr && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+
+	if rv.Kind() != reflect.Struct {
+		return "", fmt.Errorf("expected a struct or a pointer to a struct, %T given", v)
+	}
+
+	fields, err := fieldsOf(rv.Type())
+	if err != nil {
+		return "", err
+	}
+
+	numbers := make([]int64, len(fields))
+	for i, index := range fields {
+		if numbers[i], err = intField(rv.Field(index), rv.Type().Field(index).Name); err != nil {
+			return "", err
+		}
+	}
+
+	return h.Encode(numbers)
+}
+
+// DecodeInto decodes the hash into tagged fields of the struct v points to,
+// the number of decoded values must match the number of fields
+func (h *Hasher) DecodeInto(hash string, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("expected a non nil pointer to a struct, %T given", v)
+	}
+
+	rv = rv.Elem()
+
+	fields, err := fieldsOf(rv.Type())
+	if err != nil {
+		return err
+	}
+
+	numbers, err := h.Decode(hash).Unwrap()
+	if err != nil {
+		return err
+	}
+
+	if len(numbers) != len(fields) {
+		return fmt.Errorf("hash contains %d numbers, but %s has %d fields", len(numbers), rv.Type(), len(fields))
+	}
+
+	// check every number before setting any, so that v is left as it was on errors
+	for i, index := range fields {
+		f := rv.Field(index)
+
+		var overflows bool
+		if integerKinds[f.Kind()] {
+			overflows = f.OverflowInt(numbers[i])
+		} else {
+			overflows = f.OverflowUint(uint64(numbers[i]))
+		}
+
+		if overflows {
+			return fmt.Errorf("number %d overflows field %s of type %s", numbers[i], rv.Type().Field(index).Name, f.Type())
+		}
+	}
+
+	for i, index := range fields {
+		f := rv.Field(index)
+
+		if integerKinds[f.Kind()] {
+			f.SetInt(numbers[i])
+		} else {
+			f.SetUint(uint64(numbers[i]))
+		}
+	}
+
+	return nil
+}
+
+// fieldsOf the struct type, field indexes ordered by their hashid positions
+func fieldsOf(t reflect.Type) ([]int, error) {
+	if fields, ok := structFields.Load(t); ok {
+		return fields.([]int), nil
+	}
+
+	positions := make(map[int]int)
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+
+		tag, ok := f.Tag.Lookup("hashid")
+		if !ok || tag == "-" {
+			continue
+		}
+
+		position, err := strconv.Atoi(tag)
+		if err != nil || position < 0 {
+			return nil, fmt.Errorf("invalid hashid tag %q of field %s", tag, f.Name)
+		}
+
+		if f.PkgPath != "" {
+			return nil, fmt.Errorf("field %s with hashid tag must be exported", f.Name)
+		}
+
+		if _, ok := integerKinds[f.Type.Kind()]; !ok {
+			return nil, fmt.Errorf("field %s must be an integer, %s given", f.Name, f.Type)
+		}
+
+		if other, ok := positions[position]; ok {
+			return nil, fmt.Errorf("fields %s and %s have the same position %d", t.Field(other).Name, f.Name, position)
+		}
+
+		positions[position] = i
+	}
+
+	if len(positions) == 0 {
+		return nil, fmt.Errorf("%s has no fields with hashid tag", t)
+	}
+
+	fields := make([]int, len(positions))
+	for i := range fields {
+		index, ok := positions[i]
+		if !ok {
+			return nil, fmt.Errorf("%s has no field at position %d, positions must start at 0 and have no gaps", t, i)
+		}
+
+		fields[i] = index
+	}
+
+	structFields.Store(t, fields)
+
+	return fields, nil
+}
@@ -0,0 +1,121 @@
+package hashids
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type document struct {
+	Title     string
+	DocID     int64  `hashid:"2"`
+	TenantID  uint32 `hashid:"0"`
+	ProjectID int    `hashid:"1"`
+	Version   int    `hashid:"-"`
+}
+
+func Test_EncodeStructAndDecodeInto(t *testing.T) {
+	t.Parallel()
+
+	h, _ := New(Options{Salt: "struct salt", Length: 8})
+
+	doc := document{Title: "readme", TenantID: 3, ProjectID: 7, DocID: 1001, Version: 2}
+
+	hash, err := h.EncodeStruct(doc)
+	assert.NoError(t, err)
+
+	expected, _ := h.Encode(3, 7, 1001)
+	assert.Equal(t, expected, hash)
+
+	fromPointer, err := h.EncodeStruct(&doc)
+	assert.NoError(t, err)
+	assert.Equal(t, hash, fromPointer)
+
+	var decoded document
+	assert.NoError(t, h.DecodeInto(hash, &decoded))
+	assert.Equal(t, document{TenantID: 3, ProjectID: 7, DocID: 1001}, decoded)
+}
+
+func Test_DecodeIntoLeavesStructUntouchedOnOverflow(t *testing.T) {
+	t.Parallel()
+
+	h, _ := New(Options{Salt: "struct salt"})
+	large, _ := h.Encode(1, 300)
+
+	type small struct {
+		A int   `hashid:"0"`
+		B uint8 `hashid:"1"`
+	}
+
+	v := small{A: 7, B: 8}
+	assert.EqualError(t, h.DecodeInto(large, &v), "number 300 overflows field B of type uint8")
+	assert.Equal(t, small{A: 7, B: 8}, v)
+}
+
+func Test_StructErrors(t *testing.T) {
+	t.Parallel()
+
+	h, _ := New(Options{Salt: "struct salt"})
+
+	pair, _ := h.Encode(1, 2)
+	large, _ := h.Encode(1, 300)
+
+	type small struct {
+		A int   `hashid:"0"`
+		B uint8 `hashid:"1"`
+	}
+
+	type gap struct {
+		A int `hashid:"0"`
+		B int `hashid:"2"`
+	}
+
+	type duplicate struct {
+		A int `hashid:"0"`
+		B int `hashid:"0"`
+	}
+
+	type notInteger struct {
+		A string `hashid:"0"`
+	}
+
+	type unexported struct {
+		a int `hashid:"0"`
+	}
+
+	type invalidTag struct {
+		A int `hashid:"first"`
+	}
+
+	type untagged struct {
+		A int
+	}
+
+	tt := []struct {
+		name string
+		err  func() error
+		msg  string
+	}{
+		{"arity", func() error { return h.DecodeInto(pair, &document{}) }, "hash contains 2 numbers, but hashids.document has 3 fields"},
+		{"overflow", func() error { return h.DecodeInto(large, &small{}) }, "number 300 overflows field B of type uint8"},
+		{"gap", func() error { _, err := h.EncodeStruct(gap{}); return err }, "hashids.gap has no field at position 1, positions must start at 0 and have no gaps"},
+		{"duplicate", func() error { _, err := h.EncodeStruct(duplicate{}); return err }, "fields A and B have the same position 0"},
+		{"not integer", func() error { _, err := h.EncodeStruct(notInteger{}); return err }, "field A must be an integer, string given"},
+		{"unexported", func() error { _, err := h.EncodeStruct(unexported{}); return err }, "field a with hashid tag must be exported"},
+		{"invalid tag", func() error { _, err := h.EncodeStruct(invalidTag{}); return err }, `invalid hashid tag "first" of field A`},
+		{"untagged", func() error { _, err := h.EncodeStruct(untagged{}); return err }, "hashids.untagged has no fields with hashid tag"},
+		{"not struct", func() error { _, err := h.EncodeStruct(5); return err }, "expected a struct or a pointer to a struct, int given"},
+		{"not pointer", func() error { return h.DecodeInto(pair, small{}) }, "expected a non nil pointer to a struct, hashids.small given"},
+		{"negative", func() error { _, err := h.EncodeStruct(small{A: -1}); return err }, "negative numbers like -1 are not allowed"},
+	}
+
+	for _, tc := range tt {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.EqualError(t, tc.err(), tc.msg)
+		})
+	}
+}
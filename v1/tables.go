@@ -0,0 +1,231 @@
+package hashids
+
+import "fmt"
+
+const (
+	// maxTablesSize limits memory taken by the precomputed tables,
+	// counted in lookup entries over all lottery characters
+	maxTablesSize = 1 << 20
+
+	// maxStepsSize limits memory taken by the alphabets cached for the numbers
+	// after the first one of a hash, counted in symbols, every codec has its own
+	maxStepsSize = 1 << 16
+
+	// maxDenseLookup - symbols beyond it are looked up in a map
+	// unless the alphabet fills most of the range anyway
+	maxDenseLookup = 1 << 12
+)
+
+// lookup of symbol positions in an alphabet, -1 when symbol is not in it,
+// indexed by the symbol itself unless the alphabet is too sparse for that
+type lookup[T symbol] struct {
+	dense  []int32
+	sparse map[T]int32
+}
+
+func newLookup[T symbol](alphabet []T) lookup[T] {
+	size := 0
+	for _, s := range alphabet {
+		if int(s) >= size {
+			size = int(s) + 1
+		}
+	}
+
+	var l lookup[T]
+	if size > maxDenseLookup && size > 4*len(alphabet) {
+		l.sparse = make(map[T]int32, len(alphabet))
+		for i, s := range alphabet {
+			l.sparse[s] = int32(i)
+		}
+
+		return l
+	}
+
+	l.dense = make([]int32, size)
+	for i := range l.dense {
+		l.dense[i] = -1
+	}
+
+	for i, s := range alphabet {
+		l.dense[s] = int32(i)
+	}
+
+	return l
+}
+
+func (l lookup[T]) position(s T) int {
+	if l.sparse != nil {
+		if i, ok := l.sparse[s]; ok {
+			return int(i)
+		}
+
+		return -1
+	}
+
+	if int(s) >= len(l.dense) {
+		return -1
+	}
+
+	return int(l.dense[s])
+}
+
+// locate every symbol of the shuffled alphabet, positions are indexed by
+// the position of the symbol in the original alphabet
+func (l lookup[T]) locate(shuffled []T) []int32 {
+	positions := make([]int32, len(shuffled))
+	for i, s := range shuffled {
+		positions[l.position(s)] = int32(i)
+	}
+
+	return positions
+}
+
+// symbolSet - bitset of symbols
+type symbolSet[T symbol] []uint64
+
+func newSymbolSet[T symbol](symbols []T) symbolSet[T] {
+	size := 0
+	for _, s := range symbols {
+		if int(s) >= size {
+			size = int(s) + 1
+		}
+	}
+
+	set := make(symbolSet[T], (size+63)/64)
+	for _, s := range symbols {
+		set[s/64] |= 1 << (s % 64)
+	}
+
+	return set
+}
+
+func (set symbolSet[T]) has(s T) bool {
+	i := int(s / 64)
+	return i < len(set) && set[i]&(1<<(s%64)) != 0
+}
+
+// table - alphabet shuffled for a number of a hash with a given lottery,
+// positions of its symbols and, for the first number only, its first padding shuffle
+type table[T symbol] struct {
+	alphabet  []T
+	positions []int32
+	padded    []T
+}
+
+// buildTables for every lottery character, nil if they would take too much memory,
+// tables for the numbers after the first one are cached by step as they are needed
+func (c *codec[T]) buildTables() {
+	c.index = newLookup(c.alphabet)
+	c.sepSet = newSymbolSet(c.seps)
+	c.guardSet = newSymbolSet(c.guards)
+
+	if len(c.alphabet)*len(c.alphabet) > maxTablesSize {
+		return
+	}
+
+	c.tables = make([]table[T], len(c.alphabet))
+	for i, lottery := range c.alphabet {
+		c.buf = append(c.buf[:0], lottery)
+		c.buf = append(c.buf, c.salt...)
+		c.buf = append(c.buf, c.alphabet...)
+
+		alphabet := shuffle(c.alphabet, c.buf[:len(c.alphabet)])
+		c.tables[i] = table[T]{
+			alphabet:  alphabet,
+			positions: c.index.locate(alphabet),
+			padded:    shuffle(alphabet, alphabet),
+		}
+	}
+
+	c.steps = make([][]table[T], len(c.alphabet))
+}
+
+// step - alphabet for the number at position i of a hash with a given lottery,
+// prev is the alphabet of the number before it, positions are nil when the
+// alphabet is not in the tables, the result must not be modified
+func (c *codec[T]) step(i int, prev []T, lottery T, lotteryIndex int) (alphabet []T, positions []int32) {
+	if c.tables != nil && lotteryIndex >= 0 {
+		if i == 0 {
+			t := c.tables[lotteryIndex]
+			return t.alphabet, t.positions
+		}
+
+		steps := c.steps[lotteryIndex]
+		if i <= len(steps) {
+			t := steps[i-1]
+			return t.alphabet, t.positions
+		}
+
+		if i == len(steps)+1 && c.stepsSize+len(prev) <= maxStepsSize {
+			c.buf = append(c.buf[:0], lottery)
+			c.buf = append(c.buf, c.salt...)
+			c.buf = append(c.buf, prev...)
+
+			alphabet = shuffle(prev, c.buf[:len(prev)])
+			positions = c.index.locate(alphabet)
+			c.steps[lotteryIndex] = append(steps, table[T]{alphabet: alphabet, positions: positions})
+			c.stepsSize += len(alphabet)
+
+			return alphabet, positions
+		}
+	}
+
+	if i == 0 {
+		prev = c.alphabet
+	}
+
+	c.buf = append(c.buf[:0], lottery)
+	c.buf = append(c.buf, c.salt...)
+	c.buf = append(c.buf, prev...)
+	c.shuffled = append(c.shuffled[:0], prev...)
+	shuffleInPlace(c.shuffled, c.buf[:len(c.shuffled)])
+
+	return c.shuffled, nil
+}
+
+// unhashWith positions of the shuffled alphabet, see lookup.locate
+func unhashWith[T symbol](in []T, index lookup[T], positions []int32) (out int64, err error) {
+	base := int64(len(positions))
+	for _, r := range in {
+		i := index.position(r)
+		if i == -1 {
+			err = fmt.Errorf("alphabet that was used for hashing was different")
+			return
+		}
+
+		out = out*base + int64(positions[i])
+	}
+
+	return
+}
+
+// isHashOf - whether in is exactly the hash of the number
+func isHashOf[T symbol](in []T, number int64, alphabet []T) bool {
+	base := int64(len(alphabet))
+	for i := len(in) - 1; i >= 0; i-- {
+		if in[i] != alphabet[number%base] {
+			return false
+		}
+
+		// no leading zeros allowed
+		if number /= base; number == 0 {
+			return i == 0
+		}
+	}
+
+	return false
+}
+
+func equalSymbols[T symbol](a, b []T) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,101 @@
+package hashids
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"io"
+	"unicode/utf8"
+)
+
+const (
+	// DefaultTagLength of the tag in characters when Secret is set
+	DefaultTagLength = 6
+	// maxTagLength - every character takes two bytes of HMAC-SHA256
+	maxTagLength = sha256.Size / 2
+)
+
+// ErrTagMismatch - tag of the hash does not match its numbers, the hash
+// was tampered with or made with another secret
+var ErrTagMismatch = errors.New("hash tag does not match its numbers")
+
+// tagger - truncated HMAC-SHA256 of the numbers rendered in the alphabet,
+// it keeps its own buffers, so every hasher gets a clone
+type tagger struct {
+	secret   []byte
+	alphabet []rune
+	length   int
+
+	mac interface {
+		io.Writer
+		Sum(b []byte) []byte
+		Reset()
+	}
+	buf      []byte
+	sum      []byte
+	expected []byte
+}
+
+func newTagger(secret []byte, alphabet []rune, length int) *tagger {
+	return &tagger{
+		secret:   secret,
+		alphabet: alphabet,
+		length:   length,
+		mac:      hmac.New(sha256.New, secret),
+		buf:      make([]byte, 8),
+	}
+}
+
+func (t *tagger) clone() *tagger {
+	return newTagger(t.secret, t.alphabet, t.length)
+}
+
+// appendTag of the numbers to dst, every character comes from two bytes
+// of the HMAC, so the bias of taking them modulo the alphabet is negligible
+func (t *tagger) appendTag(dst []byte, numbers []int64) []byte {
+	t.mac.Reset()
+	for _, n := range numbers {
+		binary.BigEndian.PutUint64(t.buf, uint64(n))
+		t.mac.Write(t.buf)
+	}
+
+	t.sum = t.mac.Sum(t.sum[:0])
+
+	for i := 0; i < t.length; i++ {
+		d := int(binary.BigEndian.Uint16(t.sum[2*i:])) % len(t.alphabet)
+		dst = utf8.AppendRune(dst, t.alphabet[d])
+	}
+
+	return dst
+}
+
+// split the tag off the end of the input, false if the input is too short
+func (t *tagger) split(input string) (string, string, bool) {
+	end := len(input)
+	for i := 0; i < t.length; i++ {
+		if end == 0 {
+			return input, "", false
+		}
+
+		_, size := utf8.DecodeLastRuneInString(input[:end])
+		end -= size
+	}
+
+	return input[:end], input[end:], true
+}
+
+// verify the tag of the numbers in constant time
+func (t *tagger) verify(tag string, numbers []int64) bool {
+	t.expected = t.appendTag(t.expected[:0], numbers)
+	if len(tag) != len(t.expected) {
+		return false
+	}
+
+	var diff byte
+	for i := range t.expected {
+		diff |= t.expected[i] ^ tag[i]
+	}
+
+	return diff == 0
+}
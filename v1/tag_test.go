@@ -0,0 +1,142 @@
+package hashids
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testSecret = []byte("tag secret")
+
+func Test_TaggedHashes(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		name    string
+		options Options
+		length  int
+	}{
+		{"default tag length", Options{Salt: "tag salt", Secret: testSecret}, DefaultTagLength},
+		{"tag length", Options{Salt: "tag salt", Secret: testSecret, TagLength: 10, Length: 8, Prefix: "inv_"}, 10},
+		{"encrypted", Options{Salt: "tag salt", Secret: testSecret, Key: testKey}, DefaultTagLength},
+		{"unicode", Options{Salt: "tag salt", Secret: testSecret, Alphabet: "абвгдежзийклмнопрстуфхцчшщ0123456789"}, DefaultTagLength},
+	}
+
+	for _, tc := range tt {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			h, err := New(tc.options)
+			assert.NoError(t, err)
+
+			plainOptions := tc.options
+			plainOptions.Secret, plainOptions.TagLength = nil, 0
+			plain, _ := New(plainOptions)
+
+			for _, numbers := range [][]int64{{0}, {1}, {1, 2, 3}, {999999999}} {
+				hash, err := h.Encode(numbers)
+				assert.NoError(t, err)
+
+				untagged, _ := plain.Encode(numbers)
+				runes := []rune(hash)
+				assert.Equal(t, untagged, string(runes[:len(runes)-tc.length]))
+
+				length, _ := h.EncodedLen(numbers...)
+				assert.Equal(t, len(runes), length)
+
+				appended, err := h.AppendEncode(nil, numbers...)
+				assert.NoError(t, err)
+				assert.Equal(t, hash, string(appended))
+
+				decoded, err := h.Decode(hash).Unwrap()
+				assert.NoError(t, err)
+				assert.Equal(t, numbers, decoded)
+			}
+		})
+	}
+}
+
+func Test_TamperedHashes(t *testing.T) {
+	t.Parallel()
+
+	h, _ := New(Options{Salt: "tag salt", Secret: testSecret, Length: 8})
+	plain, _ := New(Options{Salt: "tag salt", Length: 8})
+	other, _ := New(Options{Salt: "tag salt", Secret: []byte("other secret"), Length: 8})
+
+	hash, _ := h.Encode(42)
+	tag := hash[len(hash)-DefaultTagLength:]
+	forged, _ := plain.Encode(43)
+	foreign, _ := other.Encode(42)
+
+	changed := []byte(hash)
+	if changed[len(changed)-1] == 'a' {
+		changed[len(changed)-1] = 'b'
+	} else {
+		changed[len(changed)-1] = 'a'
+	}
+
+	for name, input := range map[string]string{
+		"changed tag":   string(changed),
+		"forged number": forged + tag,
+		"other secret":  foreign,
+		"too short":     "abc",
+	} {
+		err := h.Decode(input).Err()
+		assert.True(t, errors.Is(err, ErrTagMismatch), name)
+	}
+
+	// a hash that is not valid at all keeps its error
+	err := h.Decode("invalid hash").Err()
+	assert.Error(t, err)
+	assert.False(t, errors.Is(err, ErrTagMismatch))
+}
+
+func Test_TagBatchesAndClones(t *testing.T) {
+	t.Parallel()
+
+	h, _ := New(Options{Salt: "tag salt", Secret: testSecret})
+
+	batch := make([][]int64, 1000)
+	for i := range batch {
+		batch[i] = []int64{int64(i)}
+	}
+
+	hashes, errs := h.EncodeBatchParallel(batch)
+	for i, hash := range hashes {
+		assert.NoError(t, errs[i])
+
+		expected, _ := h.Encode(batch[i])
+		assert.Equal(t, expected, hash)
+	}
+
+	for i, result := range h.Clone().DecodeBatchParallel(hashes) {
+		assert.Equal(t, batch[i], result.numbers)
+	}
+}
+
+func Test_InvalidTagOptions(t *testing.T) {
+	t.Parallel()
+
+	_, err := New(Options{TagLength: 4})
+	assert.EqualError(t, err, "TagLength requires Secret")
+
+	_, err = New(Options{Secret: []byte{}})
+	assert.EqualError(t, err, "Secret cannot be empty")
+
+	_, err = New(Options{Secret: testSecret, TagLength: 17})
+	assert.EqualError(t, err, "TagLength must be from 1 to 16")
+}
+
+func Test_TaggedAppendEncodeDoesNotAllocate(t *testing.T) {
+	h, _ := New(Options{Salt: "tag salt", Secret: testSecret})
+	dst := make([]byte, 0, 64)
+
+	allocs := testing.AllocsPerRun(100, func() {
+		dst, _ = h.AppendEncode(dst[:0], 1, 2, 3, 1000000)
+	})
+
+	assert.Equal(t, float64(0), allocs)
+}
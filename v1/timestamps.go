@@ -0,0 +1,108 @@
+package hashids
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+	"time"
+)
+
+// maxUnixSeconds a time can have, time.Time adds the seconds from year 1 to them
+const maxUnixSeconds = math.MaxInt64 - 62135596800
+
+// timestamps - how times are turned into numbers and back,
+// the zero value counts nanoseconds since Unix epoch in UTC
+type timestamps struct {
+	precision time.Duration
+	epoch     time.Time
+	location  *time.Location
+}
+
+func (o Options) timestamps() timestamps {
+	return timestamps{precision: o.TimePrecision, epoch: o.Epoch, location: o.Location}
+}
+
+func (ts timestamps) unit() time.Duration {
+	if ts.precision == 0 {
+		return time.Nanosecond
+	}
+
+	return ts.precision
+}
+
+func (ts timestamps) start() time.Time {
+	if ts.epoch.IsZero() {
+		return time.Unix(0, 0)
+	}
+
+	return ts.epoch
+}
+
+// number of whole units since the epoch
+func (ts timestamps) number(t time.Time) (int64, error) {
+	n, _, err := ts.units(t)
+
+	return n, err
+}
+
+// units since the epoch rounded down and whether anything was left over,
+// counted in 128 bits, as time.Duration covers only 292 years
+func (ts timestamps) units(t time.Time) (int64, bool, error) {
+	start := ts.start()
+	if t.Before(start) {
+		return 0, false, fmt.Errorf("time %s is before the epoch %s", t.Format(time.RFC3339Nano), start.Format(time.RFC3339Nano))
+	}
+
+	secs := t.Unix() - start.Unix()
+	nanos := int64(t.Nanosecond()) - int64(start.Nanosecond())
+	if nanos < 0 {
+		secs--
+		nanos += int64(time.Second)
+	}
+
+	hi, lo := bits.Mul64(uint64(secs), uint64(time.Second))
+	lo, carry := bits.Add64(lo, uint64(nanos), 0)
+	hi += carry
+
+	unit := uint64(ts.unit())
+	if hi >= unit {
+		return 0, false, ts.outOfRange(t)
+	}
+
+	n, rem := bits.Div64(hi, lo, unit)
+	if n > math.MaxInt64 {
+		return 0, false, ts.outOfRange(t)
+	}
+
+	return int64(n), rem > 0, nil
+}
+
+func (ts timestamps) outOfRange(t time.Time) error {
+	return fmt.Errorf("time %s is too far from the epoch %s", t.Format(time.RFC3339Nano), ts.start().Format(time.RFC3339Nano))
+}
+
+// time of the number of units since the epoch
+func (ts timestamps) time(n int64) (time.Time, error) {
+	if n < 0 {
+		return time.Time{}, fmt.Errorf("timestamp %d is out of range", n)
+	}
+
+	hi, lo := bits.Mul64(uint64(n), uint64(ts.unit()))
+	if hi >= uint64(time.Second) {
+		return time.Time{}, fmt.Errorf("timestamp %d is out of range", n)
+	}
+
+	secs, nanos := bits.Div64(hi, lo, uint64(time.Second))
+
+	start := ts.start()
+	if secs > uint64(maxUnixSeconds-max64(start.Unix(), 0)) {
+		return time.Time{}, fmt.Errorf("timestamp %d is out of range", n)
+	}
+
+	t := time.Unix(start.Unix()+int64(secs), int64(start.Nanosecond())+int64(nanos))
+	if ts.location == nil {
+		return t.UTC(), nil
+	}
+
+	return t.In(ts.location), nil
+}
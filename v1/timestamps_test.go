@@ -0,0 +1,131 @@
+package hashids
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_TimePrecisionAndEpoch(t *testing.T) {
+	t.Parallel()
+
+	launch := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	moment := time.Date(2026, 10, 19, 12, 30, 45, 123456789, time.UTC)
+	berlin := time.FixedZone("CET", 3600)
+
+	tt := []struct {
+		name     string
+		options  Options
+		expected time.Time
+	}{
+		{"nanoseconds", Options{Salt: "time salt"}, moment},
+		{"microseconds", Options{Salt: "time salt", TimePrecision: time.Microsecond}, moment.Truncate(time.Microsecond)},
+		{"milliseconds", Options{Salt: "time salt", TimePrecision: time.Millisecond}, moment.Truncate(time.Millisecond)},
+		{"seconds", Options{Salt: "time salt", TimePrecision: time.Second}, moment.Truncate(time.Second)},
+		{"seconds since launch", Options{Salt: "time salt", TimePrecision: time.Second, Epoch: launch}, moment.Truncate(time.Second)},
+		{"location", Options{Salt: "time salt", TimePrecision: time.Second, Location: berlin}, moment.Truncate(time.Second).In(berlin)},
+	}
+
+	lengths := make([]int, len(tt))
+
+	for i, tc := range tt {
+		h, err := New(tc.options)
+		assert.NoError(t, err)
+
+		hash, err := h.EncodeTime(moment)
+		assert.NoError(t, err)
+		lengths[i] = len(hash)
+
+		decoded, err := h.Decode(hash).AsTime()
+		assert.NoError(t, err)
+		assert.Equal(t, tc.expected, decoded, tc.name)
+	}
+
+	// coarser precision and a later epoch make shorter hashes
+	assert.Less(t, lengths[3], lengths[0])
+	assert.Less(t, lengths[4], lengths[3])
+}
+
+func Test_FarFutureTimestamps(t *testing.T) {
+	t.Parallel()
+
+	// beyond 292 years of time.Duration since the epoch
+	future := time.Date(3000, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	h, _ := New(Options{Salt: "time salt", TimePrecision: time.Second})
+	hash, err := h.EncodeTime(future)
+	assert.NoError(t, err)
+
+	decoded, err := h.Decode(hash).AsTime()
+	assert.NoError(t, err)
+	assert.Equal(t, future, decoded)
+
+	expiring, _ := New(Options{Salt: "time salt", Epoch: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)})
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	expiring.now = func() time.Time { return now }
+
+	hash, err = expiring.EncodeWithExpiry(future.Sub(now), 1)
+	assert.NoError(t, err)
+	assert.NoError(t, expiring.DecodeValid(hash, now).Err())
+
+	expiring.now = func() time.Time { return now.AddDate(1000, 0, 0) }
+	hash, err = expiring.EncodeWithExpiry(time.Duration(math.MaxInt64), 1)
+	assert.NoError(t, err)
+	assert.NoError(t, expiring.DecodeValid(hash, now.AddDate(1200, 0, 0)).Err())
+	assert.True(t, errors.Is(expiring.DecodeValid(hash, now.AddDate(1400, 0, 0)).Err(), ErrExpired))
+}
+
+func Test_AsTimeIsUTCByDefault(t *testing.T) {
+	t.Parallel()
+
+	h, _ := New(Options{Salt: "time salt"})
+
+	hash, _ := h.EncodeTime(time.Unix(1700000000, 0))
+	decoded, err := h.Decode(hash).AsTime()
+	assert.NoError(t, err)
+	assert.Equal(t, time.UTC, decoded.Location())
+	assert.Equal(t, time.Unix(1700000000, 0).UTC(), decoded)
+}
+
+func Test_TimestampErrors(t *testing.T) {
+	t.Parallel()
+
+	launch := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	h, _ := New(Options{Salt: "time salt", TimePrecision: time.Second, Epoch: launch})
+
+	_, err := h.EncodeTime(launch.Add(-time.Second))
+	assert.EqualError(t, err, "time 2024-02-29T23:59:59Z is before the epoch 2024-03-01T00:00:00Z")
+
+	huge, _ := h.Encode(math.MaxInt64)
+	_, err = h.Decode(huge).AsTime()
+	assert.EqualError(t, err, "timestamp 9223372036854775807 is out of range")
+
+	nanos, _ := New(Options{Salt: "time salt"})
+	_, err = nanos.EncodeTime(time.Date(3000, 1, 1, 0, 0, 0, 0, time.UTC))
+	assert.EqualError(t, err, "time 3000-01-01T00:00:00Z is too far from the epoch 1970-01-01T00:00:00Z")
+
+	_, err = New(Options{TimePrecision: -time.Second})
+	assert.EqualError(t, err, "TimePrecision cannot be negative")
+}
+
+func Test_ExpirySinceEpoch(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	unix, _ := New(Options{Salt: "time salt", ExpiryPrecision: time.Minute})
+	launch, _ := New(Options{Salt: "time salt", ExpiryPrecision: time.Minute, Epoch: now.Add(-time.Hour)})
+
+	unix.now = func() time.Time { return now }
+	launch.now = func() time.Time { return now }
+
+	a, _ := unix.EncodeWithExpiry(time.Hour, 1)
+	b, _ := launch.EncodeWithExpiry(time.Hour, 1)
+	assert.Less(t, len(b), len(a))
+
+	numbers, err := launch.DecodeValid(b, now).Unwrap()
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{1}, numbers)
+}
@@ -6,6 +6,11 @@ import (
 	"strings"
 )
 
+// symbol of an alphabet
+type symbol interface {
+	byte | rune
+}
+
 func createNumbersHashInt(slice []int64) int64 {
 	nh := int64(0)
 	for i, n := range slice {
@@ -15,7 +20,12 @@ func createNumbersHashInt(slice []int64) int64 {
 }
 
 func hash(in int64, alphabet []rune) []rune {
-	out := make([]rune, 0)
+	return appendHash(make([]rune, 0), in, alphabet)
+}
+
+// appendHash of the number to out
+func appendHash[T symbol](out []T, in int64, alphabet []T) []T {
+	start := len(out)
 	alphabetLength := int64(len(alphabet))
 
 	for {
@@ -27,15 +37,16 @@ func hash(in int64, alphabet []rune) []rune {
 		}
 	}
 
-	for i := len(out)/2 - 1; i >= 0; i-- {
-		j := len(out) - 1 - i
-		out[i], out[j] = out[j], out[i]
+	digits := out[start:]
+	for i := len(digits)/2 - 1; i >= 0; i-- {
+		j := len(digits) - 1 - i
+		digits[i], digits[j] = digits[j], digits[i]
 	}
 
 	return out
 }
 
-func unhash(in, alphabet []rune) (out int64, err error) {
+func unhash[T symbol](in, alphabet []T) (out int64, err error) {
 	for _, r := range in {
 		pos := -1
 		for i, s := range alphabet {
@@ -56,7 +67,7 @@ func unhash(in, alphabet []rune) (out int64, err error) {
 	return
 }
 
-func separate(in, seps []rune) (out [][]rune) {
+func separate[T symbol](in, seps []T) (out [][]T) {
 	indicies := make([]int, 0)
 	for i, r := range in {
 		for _, s := range seps {
@@ -66,7 +77,7 @@ func separate(in, seps []rune) (out [][]rune) {
 		}
 	}
 
-	out = make([][]rune, 0, len(indicies)+1)
+	out = make([][]T, 0, len(indicies)+1)
 	left := in[:]
 	for _, idx := range indicies {
 		idx -= len(in) - len(left)
@@ -78,24 +89,35 @@ func separate(in, seps []rune) (out [][]rune) {
 	return
 }
 
-func shuffle(in, salt []rune) (out []rune) {
+func shuffle[T symbol](in, salt []T) (out []T) {
 	if len(salt) == 0 {
 		out = in
 		return
 	}
 
-	out = make([]rune, len(in))
+	out = make([]T, len(in))
 	copy(out, in)
+	shuffleInPlace(out, salt)
+
+	return
+}
+
+// shuffleInPlace - salt must not share memory with the slice being shuffled
+func shuffleInPlace[T symbol](in, salt []T) {
+	if len(salt) == 0 {
+		return
+	}
+
 	p, v := 0, 0
 
 	for i := len(in) - 1; i > 0; i-- {
 		p += int(salt[v])
 		j := (int(salt[v]) + v + p) % i
-		out[i], out[j] = out[j], out[i]
-		v = (v + 1) % len(salt)
+		in[i], in[j] = in[j], in[i]
+		if v++; v == len(salt) {
+			v = 0
+		}
 	}
-
-	return
 }
 
 func hexToNums(hex string) ([]int64, error) {
@@ -159,3 +181,28 @@ func removePrefix(hash, prefix string) string {
 
 	return strings.TrimPrefix(hash, prefix)
 }
+
+// numberHashLen - count of digits of the number in a given base
+func numberHashLen(in, base int64) int {
+	length := 1
+	for in >= base {
+		in /= base
+		length++
+	}
+
+	return length
+}
+
+func min64(a, b int64) int64 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func max64(a, b int64) int64 {
+	if a > b {
+		return a
+	}
+	return b
+}
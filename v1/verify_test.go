@@ -0,0 +1,158 @@
+package hashids
+
+import (
+	"fmt"
+	"math"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// decodeVerified tells whether the structural verification accepted the input
+func decodeVerified(h *Hasher, input string) bool {
+	var verified bool
+	if h.bytes != nil {
+		_, verified, _ = h.bytes.decode(input, nil)
+	} else {
+		_, verified, _ = h.runes.decode(input, nil)
+	}
+
+	return verified
+}
+
+// checkEquivalence of decoding with the reference implementation, structural
+// verification may only accept hashes the reference implementation accepts
+func checkEquivalence(t *testing.T, h *Hasher, input string) {
+	t.Helper()
+
+	expected, expectedErr := referenceDecode(h.options, input)
+	if decodeVerified(h, input) && expectedErr != nil {
+		t.Fatalf("%s is accepted by structural verification, but rejected with %v", input, expectedErr)
+	}
+
+	actual, actualErr := h.Decode(input).Unwrap()
+	assert.Equal(t, expected, actual, input)
+	assert.Equal(t, expectedErr == nil, actualErr == nil, input)
+
+	// byte codec rejects non ASCII input straight away
+	if h.runes != nil || isASCII(input) {
+		assert.Equal(t, fmt.Sprint(expectedErr), fmt.Sprint(actualErr), input)
+	}
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+
+	return true
+}
+
+func Test_ValidHashesAreVerifiedStructurally(t *testing.T) {
+	t.Parallel()
+
+	inputs := [][]int64{
+		{0},
+		{1},
+		{1, 2, 3},
+		{45, 434, 1313, 99},
+		{math.MaxInt64},
+		{math.MaxInt64, 0, math.MaxInt64},
+	}
+
+	for i, o := range equivalenceOptions {
+		for _, length := range []int{0, 4, 16, 100} {
+			o.Length = length
+
+			h, err := New(o)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for _, input := range inputs {
+				t.Run(fmt.Sprintf("options %d length %d input %v", i, length, input), func(t *testing.T) {
+					hash, err := h.Encode(input)
+					if err != nil {
+						t.Fatal(err)
+					}
+
+					assert.True(t, decodeVerified(h, removePrefix(hash, o.Prefix)))
+					checkEquivalence(t, h, removePrefix(hash, o.Prefix))
+				})
+			}
+		}
+	}
+}
+
+func Test_StructuralVerificationRespectsMaxLength(t *testing.T) {
+	h, _ := New(Options{Salt: "test salt", Length: 4})
+	hash, _ := h.Encode(math.MaxInt64)
+
+	h, _ = New(Options{Salt: "test salt", Length: 4, MaxLength: 6})
+
+	_, err := h.Decode(hash).Unwrap()
+	assert.Contains(t, err.Error(), "error when trying to verify result: hash length")
+}
+
+// Test_DecodeExhaustively over every string up to 5 characters of a small alphabet
+func Test_DecodeExhaustively(t *testing.T) {
+	if testing.Short() {
+		t.Skip("exhaustive test is skipped in short mode")
+	}
+
+	t.Parallel()
+
+	alphabet := []rune(NumericAlphabet)
+
+	for _, length := range []int{0, 3, 5} {
+		h, err := New(Options{Salt: "test salt", Alphabet: NumericAlphabet, Length: length})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		t.Run(fmt.Sprintf("length %d", length), func(t *testing.T) {
+			input := make([]rune, 0, 5)
+
+			var walk func()
+			walk = func() {
+				checkEquivalence(t, h, string(input))
+
+				if len(input) == cap(input) {
+					return
+				}
+
+				for _, r := range alphabet {
+					input = append(input, r)
+					walk()
+					input = input[:len(input)-1]
+				}
+			}
+
+			walk()
+		})
+	}
+}
+
+func Fuzz_DecodeIsEquivalentToReference(f *testing.F) {
+	hashers := make([]*Hasher, 0, len(equivalenceOptions))
+	for _, o := range equivalenceOptions {
+		h, err := New(o)
+		if err != nil {
+			f.Fatal(err)
+		}
+
+		hashers = append(hashers, h)
+
+		for _, n := range []int64{0, 1, 1000, math.MaxInt64} {
+			hash, _ := h.Encode(n, n/2)
+			f.Add(uint8(len(hashers)-1), hash)
+		}
+	}
+
+	f.Fuzz(func(t *testing.T, i uint8, input string) {
+		checkEquivalence(t, hashers[int(i)%len(hashers)], input)
+	})
+}